@@ -0,0 +1,82 @@
+//go:build capi
+
+// pii-capi exposes the detector to C and other languages with a C FFI
+// (Python ctypes/cffi, C++, ...). It is only built with the capi tag:
+//
+//	go build -tags capi -buildmode=c-shared -o libpiicheck.so ./cmd/pii-capi
+//
+// This produces libpiicheck.so and a matching libpiicheck.h header.
+//
+// Memory ownership: every string returned by Detect is allocated with
+// C malloc and owned by the caller, who must release it with FreeString
+// (not with the host language's own allocator). Input strings are only
+// read during the call and remain owned by the caller.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/montevive/go-name-detector/pkg/detector"
+	"github.com/montevive/go-name-detector/pkg/loader"
+)
+
+var (
+	initOnce sync.Once
+	shared   *detector.Detector
+	initErr  error
+)
+
+// getDetector loads the embedded dataset on first use
+func getDetector() (*detector.Detector, error) {
+	initOnce.Do(func() {
+		l, err := loader.NewWithEmbeddedData()
+		if err != nil {
+			initErr = err
+			return
+		}
+		shared = detector.New(l.GetDataset())
+	})
+	return shared, initErr
+}
+
+// Detect analyzes a whitespace-separated name and returns the PIIResult as
+// a JSON C string, or {"error": "..."} if the dataset cannot be loaded. The
+// caller owns the returned string and must free it with FreeString.
+//
+//export Detect
+func Detect(input *C.char, threshold C.double) *C.char {
+	d, err := getDetector()
+	if err != nil {
+		return jsonCString(map[string]string{"error": err.Error()})
+	}
+
+	words := strings.Fields(C.GoString(input))
+	result := d.DetectPIIWithThreshold(words, float64(threshold))
+	return jsonCString(result)
+}
+
+// FreeString releases a string previously returned by Detect
+//
+//export FreeString
+func FreeString(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+// jsonCString marshals v into a newly allocated C string
+func jsonCString(v interface{}) *C.char {
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		jsonBytes, _ = json.Marshal(map[string]string{"error": err.Error()})
+	}
+	return C.CString(string(jsonBytes))
+}
+
+func main() {}
@@ -18,11 +18,39 @@ var (
 	dataPath   = flag.String("data", "data/combined_names.pb.gz", "Path to the protobuf data file")
 	threshold  = flag.Float64("threshold", 0.7, "Confidence threshold for PII detection")
 	jsonOutput = flag.Bool("json", false, "Output results in JSON format")
-	batch      = flag.String("batch", "", "Process names from a file (one per line)")
+	ndjson     = flag.Bool("ndjson", false, "Output one compact JSON object per line, with no batch summary object")
+	pretty     = flag.Bool("pretty", false, "Indent batch JSON output for human inspection")
+	format     = flag.String("format", "", "Batch output format: tsv, csv, json or jsonl (default: from -json and -ndjson)")
+	batch      = flag.String("batch", "", "Process names from a file (one per line), or - for stdin")
+	dedup      = flag.Bool("dedup", false, "Score identical batch lines only once")
+	batchExit  = flag.String("batch-exit", "any", "Batch lines that must be PII to exit 0: any, all or none")
+	minWords   = flag.Int("min-words", 2, "Fewest words analyzed as a name (1 allows mononyms)")
+	maxWords   = flag.Int("max-words", 6, "Most words analyzed as a name")
+	htmlOutput = flag.Bool("html", false, "Output the input text as HTML with detected names highlighted")
 	stats      = flag.Bool("stats", false, "Show dataset statistics")
 	help       = flag.Bool("help", false, "Show help information")
 )
 
+func init() {
+	// -jsonl is the name many log and warehouse tools use for the same format
+	flag.BoolVar(ndjson, "jsonl", false, "Alias for -ndjson")
+}
+
+// Exit codes: like grep, 0 when a name was detected and 1 when none was, so
+// scripts can branch on the outcome, and 2 when the input couldn't be checked
+const (
+	exitDetected    = 0
+	exitNotDetected = 1
+	exitError       = 2
+)
+
+// topCountryCount is how many countries the batch summary lists
+const topCountryCount = 5
+
+// dedupCacheSize bounds the result cache used by -dedup, so repeated lines
+// are scored once without memory growing with the batch
+const dedupCacheSize = 100000
+
 func main() {
 	flag.Parse()
 
@@ -31,20 +59,40 @@ func main() {
 		return
 	}
 
+	switch *batchExit {
+	case "any", "all", "none":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: -batch-exit must be any, all or none, got %q\n", *batchExit)
+		os.Exit(exitError)
+	}
+
+	switch *format {
+	case "", "tsv", "csv", "json", "jsonl":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: -format must be tsv, csv, json or jsonl, got %q\n", *format)
+		os.Exit(exitError)
+	}
+
 	// Load the dataset
 	fmt.Fprintf(os.Stderr, "Loading dataset from %s...\n", *dataPath)
 	startTime := time.Now()
 
 	l := loader.New()
 	if err := l.LoadFromFile(*dataPath); err != nil {
-		log.Fatalf("Failed to load dataset: %v", err)
+		fatalf("Failed to load dataset: %v", err)
 	}
 
 	loadTime := time.Since(startTime)
 	fmt.Fprintf(os.Stderr, "Dataset loaded in %v\n", loadTime)
 
 	// Create detector
-	d := detector.New(l.GetDataset())
+	detectorConfig := detector.DefaultDetectorConfig()
+	detectorConfig.MinWords = *minWords
+	detectorConfig.MaxWords = *maxWords
+	if *dedup {
+		detectorConfig.CacheSize = dedupCacheSize
+	}
+	d := detector.NewWithDetectorConfig(l.GetDataset(), detector.DefaultScoreConfig(), detectorConfig)
 
 	// Show stats if requested
 	if *stats {
@@ -52,17 +100,23 @@ func main() {
 		return
 	}
 
+	// Render highlighted HTML if requested
+	if *htmlOutput {
+		renderHTML(d)
+		return
+	}
+
 	// Process batch file if specified
 	if *batch != "" {
-		processBatchFile(*batch, d)
-		return
+		summary := processBatchFile(*batch, d)
+		os.Exit(batchExitCode(summary, *batchExit))
 	}
 
 	// Process command line arguments
 	args := flag.Args()
 	if len(args) == 0 {
 		fmt.Fprintf(os.Stderr, "Error: No input provided. Use -help for usage information.\n")
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 
 	// Join all arguments as a single string and split by spaces
@@ -73,11 +127,44 @@ func main() {
 	result := d.DetectPIIWithThreshold(words, *threshold)
 
 	// Output result
-	if *jsonOutput {
+	if *ndjson {
+		outputNDJSON(result)
+	} else if *jsonOutput {
 		outputJSON(result)
 	} else {
 		outputHuman(result, words)
 	}
+
+	if !result.IsLikelyName {
+		os.Exit(exitNotDetected)
+	}
+}
+
+// batchExitCode returns exitDetected when the batch's detected lines satisfy
+// mode: at least one ("any"), every processed line ("all") or no line
+// ("none", for gates that fail when a name leaks)
+func batchExitCode(summary types.BatchSummary, mode string) int {
+	var passed bool
+	switch mode {
+	case "all":
+		passed = summary.Processed > 0 && summary.Detected == summary.Processed
+	case "none":
+		passed = summary.Detected == 0
+	default:
+		passed = summary.Detected > 0
+	}
+
+	if passed {
+		return exitDetected
+	}
+	return exitNotDetected
+}
+
+// fatalf logs an error that kept the input from being checked and exits with
+// exitError
+func fatalf(format string, args ...interface{}) {
+	log.Printf(format, args...)
+	os.Exit(exitError)
 }
 
 func showHelp() {
@@ -93,18 +180,43 @@ Examples:
   pii-check -threshold 0.8 "Maria Garcia Lopez"
   pii-check -json "Antonio Perez"
   pii-check -batch names.txt
+  pii-check -batch names.txt -dedup
+  cat names.txt | pii-check -json -batch -
+  pii-check -ndjson -batch names.txt | jq -c 'select(.result.is_likely_name)'
+  pii-check -format csv -batch names.txt > audit.csv
+  pii-check -html "Please call José García tomorrow"
+  pii-check -html -batch document.txt > review.html
   pii-check -stats
 
 Options:
   -data <path>       Path to protobuf data file (default: data/combined_names.pb.gz)
   -threshold <val>   Confidence threshold for PII detection (default: 0.7)
   -json             Output in JSON format
-  -batch <file>     Process names from file (one per line)
+  -ndjson           Output exactly one compact JSON object per result line; in
+                    batch mode the summary object is left out
+  -jsonl            Alias for -ndjson
+  -pretty           With -json -batch, indent each result for human inspection
+  -format <f>       Batch output format, overriding -json and -ndjson: tsv,
+                    csv (one row per line with names, pattern, country and
+                    gender, for spreadsheets), json or jsonl
+  -batch <file>     Process names from file (one per line, - for stdin), writing
+                    tab-separated rows (JSON lines with -json, followed by a
+                    summary object)
+  -dedup            Score identical batch lines only once and report the dedup ratio
+  -batch-exit <m>   Batch lines that must be PII to exit 0: any (default), all,
+                    or none to fail a CI gate when any name is found
+  -min-words <n>    Fewest words analyzed as a name; 1 allows mononyms (default: 2)
+  -max-words <n>    Most words analyzed as a name (default: 6)
+  -html             Output the text (or -batch file) as HTML with names in <mark> tags
   -stats            Show dataset statistics
   -help             Show this help
 
-The tool analyzes 2-6 words to determine if they represent a PII name.
+The tool analyzes 2-6 words (see -min-words and -max-words) to determine if
+they represent a PII name. Batch lines outside those limits are skipped.
 It returns a confidence score and detailed breakdown of the analysis.
+
+Exit status is 0 when a name is detected (in batch mode, see -batch-exit),
+1 when none is, and 2 when the input or dataset could not be read.
 `)
 }
 
@@ -119,69 +231,125 @@ func showStats(d *detector.Detector, l *loader.Loader) {
 	
 	total := detectorStats["first_names_count"].(int) + detectorStats["last_names_count"].(int)
 	fmt.Printf("  Total names: %d\n", total)
+	fmt.Printf("  Countries:   %d\n", len(l.Countries()))
+	fmt.Printf("  Genders:     %s\n", strings.Join(l.Genders(), ", "))
 }
 
-func processBatchFile(filename string, d *detector.Detector) {
-	file, err := os.Open(filename)
-	if err != nil {
-		log.Fatalf("Failed to open batch file: %v", err)
+// processBatchFile streams a batch file, or stdin when filename is "-",
+// through the detector and prints a summary of the detected names, which it
+// returns
+func processBatchFile(filename string, d *detector.Detector) types.BatchSummary {
+	input := os.Stdin
+	if filename != "-" {
+		file, err := os.Open(filename)
+		if err != nil {
+			fatalf("Failed to open batch file: %v", err)
+		}
+		defer file.Close()
+		input = file
 	}
-	defer file.Close()
 
-	// Read file contents
-	content, err := os.ReadFile(filename)
-	if err != nil {
-		log.Fatalf("Failed to read batch file: %v", err)
+	fmt.Fprintf(os.Stderr, "Processing %s...\n", filename)
+
+	opts := detector.DefaultStreamOptions()
+	opts.Threshold = *threshold
+	opts.Format = detector.StreamTSV
+	switch {
+	case *format == "csv":
+		opts.Format = detector.StreamCSV
+	case *format == "tsv":
+		opts.Format = detector.StreamTSV
+	case *format == "jsonl":
+		opts.Format = detector.StreamJSONLines
+	case *format == "json" && *pretty:
+		opts.Format = detector.StreamJSONIndented
+	case *format == "json":
+		opts.Format = detector.StreamJSONLines
+	case *ndjson:
+		opts.Format = detector.StreamJSONLines
+	case *jsonOutput && *pretty:
+		opts.Format = detector.StreamJSONIndented
+	case *jsonOutput:
+		opts.Format = detector.StreamJSONLines
 	}
 
-	lines := strings.Split(string(content), "\n")
-	var processed, detected int
+	summarizer := detector.NewBatchSummarizer()
+	opts.OnResult = func(_ int, _ string, result types.PIIResult) error {
+		summarizer.Add(result)
+		return nil
+	}
 
-	fmt.Printf("Processing %d lines from %s...\n", len(lines), filename)
+	if err := d.DetectStream(input, os.Stdout, opts); err != nil {
+		fatalf("Failed to process batch file: %v", err)
+	}
 
-	for i, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
+	summary := summarizer.Summary(topCountryCount)
+	if *format == "json" || (*format == "" && *jsonOutput && !*ndjson) {
+		jsonBytes, _ := json.Marshal(map[string]interface{}{"summary": summary})
+		fmt.Println(string(jsonBytes))
+	}
 
-		words := strings.Fields(line)
-		if len(words) < 2 || len(words) > 6 {
-			continue
+	fmt.Fprintf(os.Stderr, "\nSummary: %d processed, %d detected as PII (%.1f%%)\n",
+		summary.Processed, summary.Detected, summary.DetectionRate*100)
+	if summary.Skipped > 0 {
+		fmt.Fprintf(os.Stderr, "Skipped: %d lines that could not be analyzed\n", summary.Skipped)
+	}
+	if summary.Detected > 0 {
+		fmt.Fprintf(os.Stderr, "Genders: %d Male, %d Female, %d Unisex, %d Unknown\n",
+			summary.Genders["Male"], summary.Genders["Female"], summary.Genders["Unisex"], summary.Genders["Unknown"])
+		countries := make([]string, len(summary.TopCountries))
+		for i, country := range summary.TopCountries {
+			countries[i] = fmt.Sprintf("%s (%d)", country.Country, country.Count)
+		}
+		if len(countries) > 0 {
+			fmt.Fprintf(os.Stderr, "Top countries: %s\n", strings.Join(countries, ", "))
 		}
+	}
+	if *dedup {
+		cacheStats := d.CacheStats()
+		fmt.Fprintf(os.Stderr, "Dedup: %d of %d inputs answered from the cache (%.1f%% duplicates)\n",
+			cacheStats.Hits, cacheStats.Hits+cacheStats.Misses, cacheStats.HitRate*100)
+	}
 
-		result := d.DetectPIIWithThreshold(words, *threshold)
-		processed++
+	return summary
+}
 
-		if result.IsLikelyName {
-			detected++
+// renderHTML scans the command line text, or the whole -batch file, and
+// prints it as HTML with detected names highlighted
+func renderHTML(d *detector.Detector) {
+	var text string
+	if *batch != "" {
+		content, err := os.ReadFile(*batch)
+		if err != nil {
+			fatalf("Failed to read batch file: %v", err)
 		}
-
-		if *jsonOutput {
-			output := map[string]interface{}{
-				"line":   i + 1,
-				"input":  line,
-				"result": result,
-			}
-			jsonBytes, _ := json.MarshalIndent(output, "", "  ")
-			fmt.Println(string(jsonBytes))
-		} else {
-			status := "NOT_PII"
-			if result.IsLikelyName {
-				status = "PII"
-			}
-			fmt.Printf("Line %d: %s (%.2f) - %s\n", i+1, status, result.Confidence, line)
+		text = string(content)
+	} else {
+		args := flag.Args()
+		if len(args) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: No input provided. Use -help for usage information.\n")
+			os.Exit(exitError)
 		}
+		text = strings.Join(args, " ")
 	}
 
-	fmt.Fprintf(os.Stderr, "\nSummary: %d processed, %d detected as PII (%.1f%%)\n", 
-		processed, detected, float64(detected)/float64(processed)*100)
+	opts := detector.DefaultScanOptions()
+	opts.Threshold = *threshold
+	fmt.Println(d.RenderHTMLWithOptions(text, opts))
+}
+
+func outputNDJSON(result types.PIIResult) {
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		fatalf("Failed to marshal JSON: %v", err)
+	}
+	fmt.Println(string(jsonBytes))
 }
 
 func outputJSON(result types.PIIResult) {
 	jsonBytes, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
-		log.Fatalf("Failed to marshal JSON: %v", err)
+		fatalf("Failed to marshal JSON: %v", err)
 	}
 	fmt.Println(string(jsonBytes))
 }
@@ -202,17 +370,19 @@ func outputHuman(result types.PIIResult, words []string) {
 			fmt.Printf("  Pattern: %s\n", result.Details.Pattern)
 		}
 		if result.Details.TopCountry != "" {
-			fmt.Printf("  Most likely country: %s\n", result.Details.TopCountry)
+			fmt.Printf("  Most likely country: %s\n", countryLabel(result.Details))
 		}
-		if result.Details.Gender != "" {
-			fmt.Printf("  Predicted gender: %s\n", result.Details.Gender)
+		if result.Details.Gender != "" && result.Details.Gender != "Unknown" {
+			fmt.Printf("  Predicted gender: %s (%.0f%%)\n", result.Details.Gender, result.Details.GenderConfidence*100)
 		}
+		fmt.Printf("  Decision: %s\n", result.Decision.Reason)
 	} else {
 		fmt.Printf("✗ Not a PII name (%.1f%% confidence)\n", result.Confidence*100)
 		fmt.Printf("  Input: %s\n", input)
 		if result.Details.Pattern != "" {
-			fmt.Printf("  Reason: %s\n", result.Details.Pattern)
+			fmt.Printf("  Pattern: %s\n", result.Details.Pattern)
 		}
+		fmt.Printf("  Reason: %s\n", result.Decision.Reason)
 	}
 }
 
@@ -236,4 +406,13 @@ func init() {
 			break
 		}
 	}
-}
\ No newline at end of file
+}
+
+// countryLabel formats the top country as "Spain (ES)", or the bare code when
+// its name is unknown
+func countryLabel(details types.NameDetails) string {
+	if details.TopCountryName == "" {
+		return details.TopCountry
+	}
+	return fmt.Sprintf("%s (%s)", details.TopCountryName, details.TopCountry)
+}
@@ -0,0 +1,97 @@
+// pii-grpc exposes name detection as the names.Detector gRPC service defined
+// in proto/detector.proto. The dataset is loaded once at startup and a single
+// detector serves every request.
+//
+// RPCs:
+//
+//	Detect(DetectRequest) returns (DetectResponse)
+//	DetectBatch(stream DetectRequest) returns (stream DetectResponse)
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/montevive/go-name-detector/pkg/detector"
+	"github.com/montevive/go-name-detector/pkg/grpcserver"
+	"github.com/montevive/go-name-detector/pkg/loader"
+	pb "github.com/montevive/go-name-detector/pkg/proto"
+)
+
+// shutdownTimeout is how long in-flight RPCs get to finish on SIGTERM
+const shutdownTimeout = 10 * time.Second
+
+func main() {
+	var (
+		addr      = flag.String("addr", ":9090", "listen address")
+		dataPath  = flag.String("data", "", "path to a protobuf data file (default: embedded dataset)")
+		threshold = flag.Float64("threshold", 0.7, "default confidence threshold for requests without one")
+		cacheSize = flag.Int("cache-size", 10000, "number of detection results to cache (0 disables the cache)")
+	)
+	flag.Parse()
+
+	startTime := time.Now()
+	l, err := loadDataset(*dataPath)
+	if err != nil {
+		log.Fatalf("Failed to load dataset: %v", err)
+	}
+	log.Printf("Dataset loaded in %v", time.Since(startTime))
+
+	config := detector.DefaultDetectorConfig()
+	config.CacheSize = *cacheSize
+	d := detector.NewWithDetectorConfig(l.GetDataset(), detector.DefaultScoreConfig(), config)
+
+	lis, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
+
+	grpcServer := grpc.NewServer()
+	pb.RegisterDetectorServer(grpcServer, grpcserver.New(d, *threshold))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		log.Printf("Listening on %s", lis.Addr())
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("Server failed: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Printf("Shutting down...")
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		grpcServer.Stop()
+	}
+}
+
+// loadDataset loads the dataset from path, or the embedded dataset when path
+// is empty
+func loadDataset(path string) (*loader.Loader, error) {
+	if path == "" {
+		return loader.NewWithEmbeddedData()
+	}
+
+	l := loader.New()
+	if err := l.LoadFromFile(path); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
@@ -0,0 +1,194 @@
+// pii-server exposes name detection as a JSON HTTP service for callers
+// that are not written in Go. The dataset is loaded once at startup and a
+// single detector serves every request.
+//
+// Endpoints:
+//
+//	POST /detect   {"text": "Jose Garcia", "threshold": 0.7} -> PIIResult
+//	GET  /health   {"status": "ok"}
+//	GET  /stats    dataset sizes, cache counters and uptime
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/montevive/go-name-detector/pkg/detector"
+	"github.com/montevive/go-name-detector/pkg/loader"
+)
+
+// maxRequestBytes bounds the body of a /detect request
+const maxRequestBytes = 1 << 20
+
+// shutdownTimeout is how long in-flight requests get to finish on SIGTERM
+const shutdownTimeout = 10 * time.Second
+
+// detectRequest is the body of a POST /detect request. Threshold defaults to
+// the -threshold flag when omitted.
+type detectRequest struct {
+	Text      string   `json:"text"`
+	Threshold *float64 `json:"threshold,omitempty"`
+}
+
+// server holds the state shared by all handlers
+type server struct {
+	detector  *detector.Detector
+	threshold float64
+	started   time.Time
+}
+
+func main() {
+	var (
+		addr      = flag.String("addr", ":8080", "listen address")
+		dataPath  = flag.String("data", "", "path to a protobuf data file (default: embedded dataset)")
+		threshold = flag.Float64("threshold", 0.7, "default confidence threshold for /detect")
+		cacheSize = flag.Int("cache-size", 10000, "number of detection results to cache (0 disables the cache)")
+	)
+	flag.Parse()
+
+	startTime := time.Now()
+	l, err := loadDataset(*dataPath)
+	if err != nil {
+		log.Fatalf("Failed to load dataset: %v", err)
+	}
+	log.Printf("Dataset loaded in %v", time.Since(startTime))
+
+	config := detector.DefaultDetectorConfig()
+	config.CacheSize = *cacheSize
+	s := &server{
+		detector:  detector.NewWithDetectorConfig(l.GetDataset(), detector.DefaultScoreConfig(), config),
+		threshold: *threshold,
+		started:   time.Now(),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/detect", s.handleDetect)
+	mux.HandleFunc("/health", s.handleHealth)
+	mux.HandleFunc("/stats", s.handleStats)
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		log.Printf("Listening on %s", *addr)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Server failed: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Printf("Shutting down...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Fatalf("Shutdown failed: %v", err)
+	}
+}
+
+// loadDataset loads the dataset from path, or the embedded dataset when path
+// is empty
+func loadDataset(path string) (*loader.Loader, error) {
+	if path == "" {
+		return loader.NewWithEmbeddedData()
+	}
+
+	l := loader.New()
+	if err := l.LoadFromFile(path); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// handleDetect scores the words of the request text
+func (s *server) handleDetect(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req detectRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	words := strings.Fields(req.Text)
+	if len(words) == 0 {
+		writeError(w, http.StatusBadRequest, "text is required")
+		return
+	}
+
+	threshold := s.threshold
+	if req.Threshold != nil {
+		threshold = *req.Threshold
+		if threshold < 0 || threshold > 1 {
+			writeError(w, http.StatusBadRequest, "threshold must be between 0 and 1")
+			return
+		}
+	}
+
+	result, err := s.detector.DetectPIIContext(r.Context(), words, threshold)
+	if err != nil {
+		// The client disconnected, so there is no one to answer
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleHealth reports that the server is up and the dataset is loaded
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleStats reports dataset sizes, result cache counters and uptime
+func (s *server) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"dataset":        s.detector.GetDatasetStats(),
+		"cache":          s.detector.CacheStats(),
+		"uptime_seconds": int64(time.Since(s.started).Seconds()),
+	})
+}
+
+// writeJSON writes v as the JSON response body with the given status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
+
+// writeError writes a {"error": message} response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
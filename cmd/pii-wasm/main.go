@@ -0,0 +1,82 @@
+//go:build js && wasm
+
+// pii-wasm runs the detector in the browser. It registers a global
+// JavaScript function that takes a string and returns the PIIResult as JSON:
+//
+//	GOOS=js GOARCH=wasm go build -o pii.wasm ./cmd/pii-wasm
+//
+//	const json = detectPII("John Smith", 0.7); // threshold is optional
+//	const result = JSON.parse(json);
+//
+// The dataset is embedded in the binary, so no network fetch is needed after
+// the .wasm file itself. It is decompressed on the first call.
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"syscall/js"
+
+	"github.com/montevive/go-name-detector/pkg/detector"
+	"github.com/montevive/go-name-detector/pkg/loader"
+)
+
+// defaultThreshold is used when detectPII is called without a threshold
+const defaultThreshold = 0.7
+
+var (
+	initOnce sync.Once
+	shared   *detector.Detector
+	initErr  error
+)
+
+// getDetector loads the embedded dataset on first use
+func getDetector() (*detector.Detector, error) {
+	initOnce.Do(func() {
+		l, err := loader.NewWithEmbeddedData()
+		if err != nil {
+			initErr = err
+			return
+		}
+		shared = detector.New(l.GetDataset())
+	})
+	return shared, initErr
+}
+
+// detectPII implements the JavaScript detectPII(text, threshold) function.
+// It returns the PIIResult as a JSON string, or {"error": "..."}.
+func detectPII(_ js.Value, args []js.Value) any {
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return jsonString(map[string]string{"error": "detectPII expects a text string"})
+	}
+
+	threshold := defaultThreshold
+	if len(args) > 1 && args[1].Type() == js.TypeNumber {
+		threshold = args[1].Float()
+	}
+
+	d, err := getDetector()
+	if err != nil {
+		return jsonString(map[string]string{"error": err.Error()})
+	}
+
+	words := strings.Fields(args[0].String())
+	return jsonString(d.DetectPIIWithThreshold(words, threshold))
+}
+
+// jsonString marshals v into a JSON string
+func jsonString(v interface{}) string {
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		jsonBytes, _ = json.Marshal(map[string]string{"error": err.Error()})
+	}
+	return string(jsonBytes)
+}
+
+func main() {
+	js.Global().Set("detectPII", js.FuncOf(detectPII))
+
+	// Keep the Go runtime alive so the exported function stays callable
+	select {}
+}
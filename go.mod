@@ -6,4 +6,14 @@ toolchain go1.24.4
 
 require google.golang.org/protobuf v1.36.7
 
-require golang.org/x/text v0.28.0 // indirect
+require (
+	github.com/klauspost/compress v1.18.0
+	golang.org/x/text v0.28.0
+	google.golang.org/grpc v1.74.2
+)
+
+require (
+	golang.org/x/net v0.40.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250528174236-200df99c418a // indirect
+)
@@ -0,0 +1,164 @@
+package detector
+
+import (
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+// DetectPIIDedup analyzes a batch of inputs, scoring each distinct word list
+// only once and copying its result to every repeated occurrence. Results are
+// returned in input order. Duplicates share the same underlying slices in
+// their Details, so callers must not modify them in place.
+func (d *Detector) DetectPIIDedup(inputs [][]string, threshold float64) ([]types.PIIResult, types.DedupStats) {
+	results := make([]types.PIIResult, len(inputs))
+	seen := make(map[string]int, len(inputs))
+
+	for i, words := range inputs {
+		key := strings.Join(words, "\x00")
+		if first, exists := seen[key]; exists {
+			results[i] = results[first]
+			continue
+		}
+
+		seen[key] = i
+		results[i] = d.DetectPIIWithThreshold(words, threshold)
+	}
+
+	stats := types.DedupStats{
+		Inputs: len(inputs),
+		Unique: len(seen),
+	}
+	if stats.Inputs > 0 {
+		stats.DedupRatio = 1 - float64(stats.Unique)/float64(stats.Inputs)
+	}
+
+	return results, stats
+}
+
+// DetectPIIBatch analyzes a batch of inputs on workers goroutines and
+// returns the results in input order. Zero or negative workers uses one per
+// available CPU.
+func (d *Detector) DetectPIIBatch(inputs [][]string, threshold float64, workers int) []types.PIIResult {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(inputs) {
+		workers = len(inputs)
+	}
+
+	results := make([]types.PIIResult, len(inputs))
+	indices := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				results[i] = d.DetectPIIWithThreshold(inputs[i], threshold)
+			}
+		}()
+	}
+
+	for i := range inputs {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	return results
+}
+
+// SummarizeBatch counts the detected names in a batch of results by predicted
+// gender and by top country, keeping the topCountries most frequent countries
+// (all of them when topCountries is zero or negative). Names without a
+// predicted gender are counted as "Unknown"; names without a top country are
+// left out of the country counts. Ties are broken alphabetically. Results
+// that were not analyzed are counted as skipped, apart from the processed
+// results the detection rate is based on.
+func SummarizeBatch(results []types.PIIResult, topCountries int) types.BatchSummary {
+	summarizer := NewBatchSummarizer()
+	for _, result := range results {
+		summarizer.Add(result)
+	}
+	return summarizer.Summary(topCountries)
+}
+
+// BatchSummarizer accumulates a BatchSummary one result at a time, so
+// streamed batches can be summarized without keeping their results
+type BatchSummarizer struct {
+	processed int
+	skipped   int
+	detected  int
+	genders   map[string]int
+	countries map[string]int
+}
+
+// NewBatchSummarizer creates an empty BatchSummarizer
+func NewBatchSummarizer() *BatchSummarizer {
+	return &BatchSummarizer{
+		genders:   make(map[string]int),
+		countries: make(map[string]int),
+	}
+}
+
+// Add counts a result towards the summary
+func (s *BatchSummarizer) Add(result types.PIIResult) {
+	if !result.Analyzed {
+		s.skipped++
+		return
+	}
+	s.processed++
+	if !result.IsLikelyName {
+		return
+	}
+	s.detected++
+
+	gender := result.Details.Gender
+	if gender == "" {
+		gender = genderUnknown
+	}
+	s.genders[gender]++
+
+	if country := result.Details.TopCountry; country != "" {
+		s.countries[country]++
+	}
+}
+
+// Summary returns the summary of the results added so far, as described for
+// SummarizeBatch
+func (s *BatchSummarizer) Summary(topCountries int) types.BatchSummary {
+	summary := types.BatchSummary{
+		Processed:    s.processed,
+		Skipped:      s.skipped,
+		Detected:     s.detected,
+		Genders:      make(map[string]int, len(s.genders)),
+		TopCountries: make([]types.CountryCount, 0, len(s.countries)),
+	}
+	if s.processed > 0 {
+		summary.DetectionRate = float64(s.detected) / float64(s.processed)
+	}
+	for gender, count := range s.genders {
+		summary.Genders[gender] = count
+	}
+
+	for country, count := range s.countries {
+		summary.TopCountries = append(summary.TopCountries, types.CountryCount{Country: country, Count: count})
+	}
+	sort.Slice(summary.TopCountries, func(i, j int) bool {
+		a, b := summary.TopCountries[i], summary.TopCountries[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Country < b.Country
+	})
+	if topCountries > 0 && len(summary.TopCountries) > topCountries {
+		summary.TopCountries = summary.TopCountries[:topCountries]
+	}
+
+	return summary
+}
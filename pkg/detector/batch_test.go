@@ -0,0 +1,185 @@
+package detector
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+func TestDetectPIIDedup(t *testing.T) {
+	detector := New(createTestDataset())
+
+	inputs := [][]string{
+		{"John", "Smith"},
+		{"Jose", "Garcia"},
+		{"John", "Smith"},
+		{"The", "Quick", "Fox"},
+		{"John", "Smith"},
+	}
+
+	results, stats := detector.DetectPIIDedup(inputs, 0.7)
+
+	if len(results) != len(inputs) {
+		t.Fatalf("Expected %d results, got %d", len(inputs), len(results))
+	}
+	for i, words := range inputs {
+		expected := detector.DetectPIIWithThreshold(words, 0.7)
+		if results[i].IsLikelyName != expected.IsLikelyName || results[i].Confidence != expected.Confidence {
+			t.Errorf("Input %d %v: expected %v (%.3f), got %v (%.3f)", i, words,
+				expected.IsLikelyName, expected.Confidence, results[i].IsLikelyName, results[i].Confidence)
+		}
+	}
+
+	if stats.Inputs != 5 || stats.Unique != 3 {
+		t.Errorf("Expected 5 inputs and 3 unique, got %+v", stats)
+	}
+	if stats.DedupRatio != 0.4 {
+		t.Errorf("Expected dedup ratio 0.4, got %v", stats.DedupRatio)
+	}
+
+	if _, empty := detector.DetectPIIDedup(nil, 0.7); empty.DedupRatio != 0 {
+		t.Errorf("Expected zero dedup ratio for an empty batch, got %v", empty.DedupRatio)
+	}
+}
+
+func TestDetectPIIBatch(t *testing.T) {
+	detector := New(createTestDataset())
+	inputs := batchInputs(50)
+
+	for _, workers := range []int{0, 1, 4, 100} {
+		results := detector.DetectPIIBatch(inputs, 0.7, workers)
+		if len(results) != len(inputs) {
+			t.Fatalf("workers=%d: expected %d results, got %d", workers, len(inputs), len(results))
+		}
+		for i, words := range inputs {
+			expected := detector.DetectPIIWithThreshold(words, 0.7)
+			if results[i].IsLikelyName != expected.IsLikelyName || results[i].Confidence != expected.Confidence ||
+				!equalStringSlices(results[i].Details.FirstNames, expected.Details.FirstNames) {
+				t.Errorf("workers=%d: input %d %v out of order or wrong: got %v (%.3f)", workers, i, words,
+					results[i].IsLikelyName, results[i].Confidence)
+			}
+		}
+	}
+
+	if results := detector.DetectPIIBatch(nil, 0.7, 4); len(results) != 0 {
+		t.Errorf("Expected no results for an empty batch, got %d", len(results))
+	}
+}
+
+// batchInputs returns n inputs cycling through names and non-names
+func batchInputs(n int) [][]string {
+	samples := [][]string{
+		{"Jose", "Manuel", "Garcia", "Lopez"},
+		{"John", "Smith"},
+		{"Maria", "Robles", "Hermoso"},
+		{"The", "Quick", "Fox"},
+		{"Garcia", "Jose"},
+	}
+
+	inputs := make([][]string, n)
+	for i := range inputs {
+		inputs[i] = samples[i%len(samples)]
+	}
+	return inputs
+}
+
+func TestSummarizeBatch(t *testing.T) {
+	detected := func(gender, country string) types.PIIResult {
+		return types.PIIResult{IsLikelyName: true, Analyzed: true, Details: types.NameDetails{Gender: gender, TopCountry: country}}
+	}
+	results := []types.PIIResult{
+		detected("Male", "ES"),
+		detected("Female", "MX"),
+		detected("Male", "ES"),
+		detected("", "US"),
+		detected("Female", ""),
+		{IsLikelyName: false, Analyzed: true, Details: types.NameDetails{Gender: "Male", TopCountry: "GB"}},
+		rejectedResult("invalid_length", 0.7),
+	}
+
+	summary := SummarizeBatch(results, 2)
+
+	// Unanalyzed results are skipped rather than processed
+	if summary.Processed != 6 || summary.Skipped != 1 || summary.Detected != 5 {
+		t.Errorf("Expected 6 processed, 1 skipped and 5 detected, got %d, %d and %d", summary.Processed, summary.Skipped, summary.Detected)
+	}
+	for gender, expected := range map[string]int{"Male": 2, "Female": 2, "Unknown": 1} {
+		if summary.Genders[gender] != expected {
+			t.Errorf("Expected %d %s, got %d", expected, gender, summary.Genders[gender])
+		}
+	}
+
+	expectedCountries := []types.CountryCount{{Country: "ES", Count: 2}, {Country: "MX", Count: 1}}
+	if len(summary.TopCountries) != len(expectedCountries) {
+		t.Fatalf("Expected top countries %v, got %v", expectedCountries, summary.TopCountries)
+	}
+	for i, expected := range expectedCountries {
+		if summary.TopCountries[i] != expected {
+			t.Errorf("Top country %d: expected %v, got %v", i, expected, summary.TopCountries[i])
+		}
+	}
+
+	if all := SummarizeBatch(results, 0); len(all.TopCountries) != 3 {
+		t.Errorf("Expected all 3 countries without a limit, got %v", all.TopCountries)
+	}
+	if summary.DetectionRate != 5.0/6.0 {
+		t.Errorf("Expected a detection rate of 5/6, got %v", summary.DetectionRate)
+	}
+}
+
+func TestBatchSummarizer_NothingProcessed(t *testing.T) {
+	detector := New(createTestDataset())
+
+	for name, input := range map[string]string{
+		"empty file":      "",
+		"all-blank lines": "\n   \n\t\n\n",
+	} {
+		summarizer := NewBatchSummarizer()
+		opts := DefaultStreamOptions()
+		opts.OnResult = func(_ int, _ string, result types.PIIResult) error {
+			summarizer.Add(result)
+			return nil
+		}
+
+		var out bytes.Buffer
+		if err := detector.DetectStream(strings.NewReader(input), &out, opts); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		summary := summarizer.Summary(5)
+		if summary.Processed != 0 || summary.Detected != 0 {
+			t.Errorf("%s: expected nothing processed, got %d processed and %d detected", name, summary.Processed, summary.Detected)
+		}
+		if summary.DetectionRate != 0 {
+			t.Errorf("%s: expected a detection rate of 0, got %v", name, summary.DetectionRate)
+		}
+		if _, err := json.Marshal(summary); err != nil {
+			t.Errorf("%s: expected the summary to marshal, got %v", name, err)
+		}
+	}
+}
+
+func BenchmarkDetectPIIBatch_Sequential(b *testing.B) {
+	detector := New(createTestDataset())
+	inputs := batchInputs(1000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, words := range inputs {
+			detector.DetectPIIWithThreshold(words, 0.7)
+		}
+	}
+}
+
+func BenchmarkDetectPIIBatch_Parallel(b *testing.B) {
+	detector := New(createTestDataset())
+	inputs := batchInputs(1000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		detector.DetectPIIBatch(inputs, 0.7, 0)
+	}
+}
@@ -0,0 +1,186 @@
+package detector
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+// cacheKey identifies a detection by its input words and threshold
+type cacheKey struct {
+	words     string
+	threshold float64
+}
+
+// cacheEntry is the value stored in each element of the LRU list
+type cacheEntry struct {
+	key    cacheKey
+	result types.PIIResult
+}
+
+// resultCache is a fixed-size LRU cache of detection results. It is safe for
+// concurrent use; get takes the write lock since a hit reorders the LRU list.
+type resultCache struct {
+	mu        sync.RWMutex
+	maxSize   int
+	order     *list.List // Front is the most recently used entry
+	entries   map[cacheKey]*list.Element
+	hits      int64
+	misses    int64
+	evictions int64
+	epoch     int64 // Incremented by clear so results scored before it are dropped
+}
+
+// newResultCache creates a cache holding at most maxSize results
+func newResultCache(maxSize int) *resultCache {
+	return &resultCache{
+		maxSize: maxSize,
+		order:   list.New(),
+		entries: make(map[cacheKey]*list.Element, maxSize),
+	}
+}
+
+// newCacheKey builds the cache key for a word list and threshold
+func newCacheKey(words []string, threshold float64) cacheKey {
+	return cacheKey{words: strings.Join(words, "\x00"), threshold: threshold}
+}
+
+// get returns the cached result for key and marks it as recently used. On a
+// miss it returns the epoch to pass to put along with the scored result.
+func (c *resultCache) get(key cacheKey) (types.PIIResult, int64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, exists := c.entries[key]
+	if !exists {
+		c.misses++
+		return types.PIIResult{}, c.epoch, false
+	}
+
+	c.hits++
+	c.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).result, c.epoch, true
+}
+
+// put stores a result scored during epoch, evicting the least recently used
+// entry when full. Results from before the last clear are discarded.
+func (c *resultCache) put(key cacheKey, result types.PIIResult, epoch int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if epoch != c.epoch {
+		return
+	}
+
+	if elem, exists := c.entries[key]; exists {
+		elem.Value.(*cacheEntry).result = result
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	for c.order.Len() >= c.maxSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+		c.evictions++
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, result: result})
+}
+
+// clear drops every cached result, keeping the counters
+func (c *resultCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.entries = make(map[cacheKey]*list.Element, c.maxSize)
+	c.epoch++
+}
+
+// stats returns a snapshot of the cache counters
+func (c *resultCache) stats() types.CacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := types.CacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Size:      c.order.Len(),
+		MaxSize:   c.maxSize,
+	}
+	if lookups := c.hits + c.misses; lookups > 0 {
+		stats.HitRate = float64(c.hits) / float64(lookups)
+	}
+	return stats
+}
+
+// keyEntry is the value stored in each element of a keyCache's LRU list
+type keyEntry struct {
+	name string
+	key  string
+}
+
+// keyCache is a fixed-size LRU cache mapping raw names to their normalized
+// lookup keys, so the Unicode transforms of a name seen before are skipped.
+// It is safe for concurrent use.
+type keyCache struct {
+	mu      sync.Mutex
+	maxSize int
+	order   *list.List // Front is the most recently used entry
+	entries map[string]*list.Element
+}
+
+// newKeyCache creates a cache holding at most maxSize keys
+func newKeyCache(maxSize int) *keyCache {
+	return &keyCache{
+		maxSize: maxSize,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, maxSize),
+	}
+}
+
+// get returns the cached key for name and marks it as recently used
+func (c *keyCache) get(name string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, exists := c.entries[name]
+	if !exists {
+		return "", false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*keyEntry).key, true
+}
+
+// put stores the key of name, evicting the least recently used entry when
+// full
+func (c *keyCache) put(name, key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, exists := c.entries[name]; exists {
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	for c.order.Len() >= c.maxSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*keyEntry).name)
+	}
+
+	c.entries[name] = c.order.PushFront(&keyEntry{name: name, key: key})
+}
+
+// CacheStats reports the result cache's hit rate, eviction count and current
+// size. It returns zero stats when the cache is disabled.
+func (d *Detector) CacheStats() types.CacheStats {
+	if d.cache == nil {
+		return types.CacheStats{}
+	}
+	return d.cache.stats()
+}
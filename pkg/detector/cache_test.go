@@ -0,0 +1,90 @@
+package detector
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestResultCache_BoundedUnderChurn(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.CacheSize = 8
+	detector := NewWithDetectorConfig(createTestDataset(), DefaultScoreConfig(), config)
+
+	for i := 0; i < 100; i++ {
+		detector.DetectPII([]string{"Maria", fmt.Sprintf("Garcia%d", i)})
+		if size := detector.CacheStats().Size; size > config.CacheSize {
+			t.Fatalf("Cache grew to %d entries, limit is %d", size, config.CacheSize)
+		}
+	}
+
+	stats := detector.CacheStats()
+	if stats.Size != config.CacheSize {
+		t.Errorf("Expected full cache of %d entries, got %d", config.CacheSize, stats.Size)
+	}
+	if stats.Evictions != 100-int64(config.CacheSize) {
+		t.Errorf("Expected %d evictions, got %d", 100-config.CacheSize, stats.Evictions)
+	}
+}
+
+func TestResultCache_HitsAndRecency(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.CacheSize = 2
+	detector := NewWithDetectorConfig(createTestDataset(), DefaultScoreConfig(), config)
+
+	first := detector.DetectPII([]string{"Jose", "Garcia"})
+	detector.DetectPII([]string{"John", "Smith"})
+	// Touch the first entry so the second becomes least recently used
+	if cached := detector.DetectPII([]string{"Jose", "Garcia"}); cached.Confidence != first.Confidence {
+		t.Errorf("Cached confidence %.3f differs from original %.3f", cached.Confidence, first.Confidence)
+	}
+	detector.DetectPII([]string{"Maria", "Lopez"})
+	detector.DetectPII([]string{"Jose", "Garcia"})
+
+	stats := detector.CacheStats()
+	if stats.Hits != 2 || stats.Misses != 3 {
+		t.Errorf("Expected 2 hits and 3 misses, got %d and %d", stats.Hits, stats.Misses)
+	}
+	if stats.HitRate != 0.4 {
+		t.Errorf("Expected hit rate 0.4, got %.3f", stats.HitRate)
+	}
+}
+
+func TestResultCache_Disabled(t *testing.T) {
+	detector := New(createTestDataset())
+	detector.DetectPII([]string{"Jose", "Garcia"})
+
+	if stats := detector.CacheStats(); stats.Size != 0 || stats.Hits+stats.Misses != 0 {
+		t.Errorf("Expected empty stats with caching disabled, got %+v", stats)
+	}
+}
+
+func TestKeyCache_Normalization(t *testing.T) {
+	config := DefaultScoreConfig()
+	config.NormalizationCacheSize = 2
+	scorer := NewScorer(createTestDataset(), config)
+
+	for _, name := range []string{"José", "María", "García", "José", "Smith"} {
+		if _, exists := scorer.lookup(name, name != "García" && name != "Smith"); !exists {
+			t.Errorf("Expected %q to be found with the normalization cache", name)
+		}
+	}
+
+	// ASCII names are never cached, and the least recently used name is evicted
+	if scorer.keys.order.Len() != 2 {
+		t.Fatalf("Expected 2 cached keys, got %d", scorer.keys.order.Len())
+	}
+	if _, exists := scorer.keys.get("María"); exists {
+		t.Errorf("Expected %q to be evicted", "María")
+	}
+	if key, exists := scorer.keys.get("José"); !exists || key != "JOSE" {
+		t.Errorf("Expected %q cached as JOSE, got %q (%v)", "José", key, exists)
+	}
+	if _, exists := scorer.keys.get("Smith"); exists {
+		t.Errorf("Expected ASCII names not to be cached")
+	}
+
+	config.NormalizationCacheSize = 0
+	if NewScorer(createTestDataset(), config).keys != nil {
+		t.Errorf("Expected no cache when NormalizationCacheSize is 0")
+	}
+}
@@ -0,0 +1,78 @@
+package detector
+
+import (
+	"math"
+	"sort"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+// DetectPIIAllCombinations returns every first name/surname split of words
+// with its score, highest first. It is DetectCandidates without a limit.
+func (d *Detector) DetectPIIAllCombinations(words []string) []types.ScoredCombination {
+	return d.DetectCandidates(words, 0)
+}
+
+// DetectCandidates scores every first name/surname split of words and
+// returns the n best, highest score first, to show the runner-up readings of
+// an ambiguous name. Ties are ordered as DetectPII breaks them, so the first
+// candidate is the split DetectPII picks. A non-positive n returns every
+// split. Input DetectPII can't analyze yields no candidates.
+func (d *Detector) DetectCandidates(words []string, n int) []types.ScoredCombination {
+	minWords, maxWords := d.config.wordLimits()
+	if len(words) < minWords || len(words) > maxWords {
+		return nil
+	}
+
+	cleanWords, positions := d.cleanWords(words)
+	cleanWords, _, _ = d.composePrefixes(cleanWords, positions)
+	if len(cleanWords) < min(minWords, 2) {
+		return nil
+	}
+
+	combinations, _ := d.limitCombinations(d.generateCombinations(cleanWords))
+	scores := make([]float64, len(combinations))
+	for i, combo := range combinations {
+		scores[i] = d.scorer.clampedScore(combo)
+	}
+
+	order := make([]int, len(combinations))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if math.Abs(scores[a]-scores[b]) > scoreTieEpsilon {
+			return scores[a] > scores[b]
+		}
+		return d.scorer.breaksTie(combinations[a], combinations[b])
+	})
+
+	if n <= 0 || n > len(order) {
+		n = len(order)
+	}
+
+	candidates := make([]types.ScoredCombination, n)
+	for i, index := range order[:n] {
+		combo := combinations[index]
+		gender, _ := d.scorer.PredictGender(combo)
+		firstNames, surnames := combo.FirstNames, combo.Surnames
+		if d.config.NormalizeOutput {
+			firstNames = normalizeTokens(firstNames)
+			surnames = normalizeTokens(surnames)
+		}
+		firstNames, surnames = d.caseNames(firstNames, surnames)
+
+		candidates[i] = types.ScoredCombination{
+			FirstNames: firstNames,
+			Surnames:   surnames,
+			Reversed:   combo.Reversed,
+			Score:      d.scorer.config.Calibration.Apply(scores[index]),
+			Pattern:    d.buildPattern(combo),
+			TopCountry: d.scorer.GetTopCountry(combo),
+			Gender:     gender,
+		}
+	}
+
+	return candidates
+}
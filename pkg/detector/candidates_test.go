@@ -0,0 +1,45 @@
+package detector
+
+import "testing"
+
+func TestDetectCandidates(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.AllowSurnameFirst = true
+	detector := NewWithDetectorConfig(createTestDataset(), DefaultScoreConfig(), config)
+	words := []string{"Jose", "Manuel", "Garcia"}
+
+	all := detector.DetectCandidates(words, 0)
+	if len(all) != 4 {
+		t.Fatalf("Expected 4 splits (2 forward, 2 surname-first), got %d", len(all))
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i].Score > all[i-1].Score {
+			t.Errorf("Expected candidates sorted by score, got %.3f after %.3f", all[i].Score, all[i-1].Score)
+		}
+	}
+
+	// The best candidate is the split DetectPII picks
+	result := detector.DetectPII(words)
+	best := all[0]
+	if !equalStringSlices(best.FirstNames, result.Details.FirstNames) || !equalStringSlices(best.Surnames, result.Details.Surnames) {
+		t.Errorf("Expected best candidate %v/%v, got %v/%v", result.Details.FirstNames, result.Details.Surnames, best.FirstNames, best.Surnames)
+	}
+	if best.Score != result.Confidence || best.Pattern != result.Details.Pattern {
+		t.Errorf("Expected best candidate to match DetectPII (%.3f, %s), got (%.3f, %s)", result.Confidence, result.Details.Pattern, best.Score, best.Pattern)
+	}
+	if best.TopCountry == "" || best.Gender != "Male" {
+		t.Errorf("Expected country and gender for the best candidate, got %q and %q", best.TopCountry, best.Gender)
+	}
+
+	if every := detector.DetectPIIAllCombinations(words); len(every) != len(all) {
+		t.Errorf("Expected DetectPIIAllCombinations to return all %d splits, got %d", len(all), len(every))
+	}
+
+	if top := detector.DetectCandidates(words, 2); len(top) != 2 || top[1].Score != all[1].Score {
+		t.Errorf("Expected the top 2 candidates, got %+v", top)
+	}
+
+	if candidates := detector.DetectCandidates([]string{"Jose"}, 3); candidates != nil {
+		t.Errorf("Expected no candidates for a single word, got %+v", candidates)
+	}
+}
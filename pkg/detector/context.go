@@ -0,0 +1,42 @@
+package detector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+// ErrCanceled is returned by the context-aware detection methods when their
+// context is canceled or times out before detection finishes. The returned
+// error also wraps the context's own error.
+var ErrCanceled = errors.New("detection canceled")
+
+// canceled returns ErrCanceled, wrapping the cause, once ctx is done
+func canceled(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrCanceled, err)
+	}
+	return nil
+}
+
+// DetectPIIContext is DetectPIIWithThreshold, checking ctx between scored
+// combinations and returning ErrCanceled as soon as it is done
+func (d *Detector) DetectPIIContext(ctx context.Context, words []string, threshold float64) (types.PIIResult, error) {
+	if d.cache == nil {
+		return d.detectContext(ctx, words, threshold, nil)
+	}
+
+	key := newCacheKey(words, threshold)
+	result, epoch, ok := d.cache.get(key)
+	if ok {
+		return result, nil
+	}
+	result, err := d.detectContext(ctx, words, threshold, nil)
+	if err != nil {
+		return result, err
+	}
+	d.cache.put(key, result, epoch)
+	return result, nil
+}
@@ -0,0 +1,71 @@
+package detector
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+func TestDetectPIIContext(t *testing.T) {
+	detector := New(createTestDataset())
+	words := []string{"Jose", "Garcia"}
+
+	result, err := detector.DetectPIIContext(context.Background(), words, 0.7)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := detector.DetectPIIWithThreshold(words, 0.7)
+	if result.IsLikelyName != expected.IsLikelyName || result.Confidence != expected.Confidence {
+		t.Errorf("Expected the same result as DetectPIIWithThreshold (%v, %.4f), got (%v, %.4f)",
+			expected.IsLikelyName, expected.Confidence, result.IsLikelyName, result.Confidence)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := detector.DetectPIIContext(ctx, []string{"John", "Smith"}, 0.7); !errors.Is(err, ErrCanceled) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected ErrCanceled wrapping context.Canceled, got %v", err)
+	}
+}
+
+func TestScanReaderContext_Canceled(t *testing.T) {
+	detector := New(createTestDataset())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	input := "login by Jose Garcia\nnothing here\nJohn Smith logged out\n"
+	calls := 0
+	err := detector.ScanReaderContext(ctx, strings.NewReader(input), DefaultScanOptions(), func(types.NameMatch) error {
+		calls++
+		cancel()
+		return nil
+	})
+
+	if !errors.Is(err, ErrCanceled) || calls != 1 {
+		t.Errorf("Expected the scan to stop with ErrCanceled after the first match, got err=%v calls=%d", err, calls)
+	}
+}
+
+func TestDetectStreamContext_Canceled(t *testing.T) {
+	detector := New(createTestDataset())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	opts := DefaultStreamOptions()
+	opts.OnResult = func(int, string, types.PIIResult) error {
+		cancel()
+		return nil
+	}
+
+	var out bytes.Buffer
+	err := detector.DetectStreamContext(ctx, strings.NewReader("Jose Garcia\nJohn Smith\n"), &out, opts)
+	if !errors.Is(err, ErrCanceled) {
+		t.Fatalf("Expected ErrCanceled, got %v", err)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 1 {
+		t.Errorf("Expected the result scored before cancellation to be flushed, got %d lines", lines)
+	}
+}
@@ -0,0 +1,132 @@
+package detector
+
+import "strings"
+
+// countryNames maps ISO 3166-1 alpha-2 codes to English country names. It
+// covers every country in the embedded dataset plus the Spanish- and
+// Portuguese-speaking countries it lacks.
+var countryNames = map[string]string{
+	"AE": "United Arab Emirates",
+	"AF": "Afghanistan",
+	"AL": "Albania",
+	"AO": "Angola",
+	"AR": "Argentina",
+	"AT": "Austria",
+	"AZ": "Azerbaijan",
+	"BD": "Bangladesh",
+	"BE": "Belgium",
+	"BF": "Burkina Faso",
+	"BG": "Bulgaria",
+	"BH": "Bahrain",
+	"BI": "Burundi",
+	"BN": "Brunei",
+	"BO": "Bolivia",
+	"BR": "Brazil",
+	"BW": "Botswana",
+	"CA": "Canada",
+	"CH": "Switzerland",
+	"CL": "Chile",
+	"CM": "Cameroon",
+	"CN": "China",
+	"CO": "Colombia",
+	"CR": "Costa Rica",
+	"CU": "Cuba",
+	"CV": "Cape Verde",
+	"CY": "Cyprus",
+	"CZ": "Czechia",
+	"DE": "Germany",
+	"DJ": "Djibouti",
+	"DK": "Denmark",
+	"DO": "Dominican Republic",
+	"DZ": "Algeria",
+	"EC": "Ecuador",
+	"EE": "Estonia",
+	"EG": "Egypt",
+	"ES": "Spain",
+	"ET": "Ethiopia",
+	"FI": "Finland",
+	"FJ": "Fiji",
+	"FR": "France",
+	"GB": "United Kingdom",
+	"GE": "Georgia",
+	"GH": "Ghana",
+	"GQ": "Equatorial Guinea",
+	"GR": "Greece",
+	"GT": "Guatemala",
+	"GW": "Guinea-Bissau",
+	"HK": "Hong Kong",
+	"HN": "Honduras",
+	"HR": "Croatia",
+	"HT": "Haiti",
+	"HU": "Hungary",
+	"ID": "Indonesia",
+	"IE": "Ireland",
+	"IL": "Israel",
+	"IN": "India",
+	"IQ": "Iraq",
+	"IR": "Iran",
+	"IS": "Iceland",
+	"IT": "Italy",
+	"JM": "Jamaica",
+	"JO": "Jordan",
+	"JP": "Japan",
+	"KH": "Cambodia",
+	"KR": "South Korea",
+	"KW": "Kuwait",
+	"KZ": "Kazakhstan",
+	"LB": "Lebanon",
+	"LT": "Lithuania",
+	"LU": "Luxembourg",
+	"LY": "Libya",
+	"MA": "Morocco",
+	"MD": "Moldova",
+	"MO": "Macao",
+	"MT": "Malta",
+	"MU": "Mauritius",
+	"MV": "Maldives",
+	"MX": "Mexico",
+	"MY": "Malaysia",
+	"MZ": "Mozambique",
+	"NA": "Namibia",
+	"NG": "Nigeria",
+	"NI": "Nicaragua",
+	"NL": "Netherlands",
+	"NO": "Norway",
+	"OM": "Oman",
+	"PA": "Panama",
+	"PE": "Peru",
+	"PH": "Philippines",
+	"PL": "Poland",
+	"PR": "Puerto Rico",
+	"PS": "Palestine",
+	"PT": "Portugal",
+	"PY": "Paraguay",
+	"QA": "Qatar",
+	"RS": "Serbia",
+	"RU": "Russia",
+	"SA": "Saudi Arabia",
+	"SD": "Sudan",
+	"SE": "Sweden",
+	"SG": "Singapore",
+	"SI": "Slovenia",
+	"ST": "São Tomé and Príncipe",
+	"SV": "El Salvador",
+	"SY": "Syria",
+	"TL": "Timor-Leste",
+	"TM": "Turkmenistan",
+	"TN": "Tunisia",
+	"TR": "Turkey",
+	"TW": "Taiwan",
+	"US": "United States",
+	"UY": "Uruguay",
+	"VE": "Venezuela",
+	"YE": "Yemen",
+	"ZA": "South Africa",
+}
+
+// CountryName returns the English name of an ISO 3166-1 alpha-2 country code
+// ("ES" -> "Spain"), or an empty string for unknown codes. Matching is
+// case-insensitive.
+func CountryName(code string) string {
+	return countryNames[strings.ToUpper(strings.TrimSpace(code))]
+}
@@ -0,0 +1,25 @@
+package detector
+
+import "testing"
+
+func TestCountryName(t *testing.T) {
+	tests := map[string]string{
+		"ES":  "Spain",
+		"mx":  "Mexico",
+		"GB":  "United Kingdom",
+		"XX":  "",
+		"":    "",
+		"ESP": "",
+	}
+
+	for code, expected := range tests {
+		if got := CountryName(code); got != expected {
+			t.Errorf("CountryName(%q) = %q, want %q", code, got, expected)
+		}
+	}
+
+	result := New(createTestDataset()).DetectPII([]string{"Jose", "Garcia"})
+	if result.Details.TopCountryName != CountryName(result.Details.TopCountry) || result.Details.TopCountryName == "" {
+		t.Errorf("Expected TopCountryName for %q, got %q", result.Details.TopCountry, result.Details.TopCountryName)
+	}
+}
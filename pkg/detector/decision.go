@@ -0,0 +1,77 @@
+package detector
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+// maxReasonFactors is the number of factors quoted in a Decision's Reason
+const maxReasonFactors = 2
+
+// buildDecision explains the threshold decision of a result from the
+// factors of its winning combination
+func buildDecision(result types.PIIResult, threshold float64, factors []types.Factor) types.Decision {
+	decision := types.Decision{
+		Threshold:  threshold,
+		Confidence: result.Confidence,
+		Passed:     result.IsLikelyName,
+	}
+
+	for _, factor := range factors {
+		if factor.Impact > 0 {
+			decision.Supporting = append(decision.Supporting, factor)
+		} else {
+			decision.Detracting = append(decision.Detracting, factor)
+		}
+	}
+	sortByImpact(decision.Supporting)
+	sortByImpact(decision.Detracting)
+
+	switch result.Details.Pattern {
+	case "invalid_length":
+		decision.Reason = "Rejected: a name needs 2 to 6 words"
+		return decision
+	case "insufficient_words":
+		decision.Reason = "Rejected: fewer than two words could be part of a name"
+		return decision
+	}
+
+	// Lead with what drove the decision: supporting factors for a pass,
+	// detracting ones (then the weak support) for a rejection
+	var quoted []types.Factor
+	if decision.Passed {
+		quoted = decision.Supporting
+	} else {
+		quoted = append(append(quoted, decision.Detracting...), decision.Supporting...)
+	}
+	if len(quoted) > maxReasonFactors {
+		quoted = quoted[:maxReasonFactors]
+	}
+
+	details := make([]string, len(quoted))
+	for i, factor := range quoted {
+		details[i] = factor.Detail
+	}
+	if len(details) == 0 {
+		details = append(details, "no token matched a known name")
+	}
+
+	if decision.Passed {
+		decision.Reason = fmt.Sprintf("Passed: %s (%.2f >= %.2f)", strings.Join(details, ", "), result.Confidence, threshold)
+	} else {
+		decision.Reason = fmt.Sprintf("Rejected: %s (%.2f < %.2f)", strings.Join(details, ", "), result.Confidence, threshold)
+	}
+
+	return decision
+}
+
+// sortByImpact orders factors from the largest to the smallest absolute impact
+func sortByImpact(factors []types.Factor) {
+	sort.SliceStable(factors, func(i, j int) bool {
+		return math.Abs(factors[i].Impact) > math.Abs(factors[j].Impact)
+	})
+}
@@ -0,0 +1,132 @@
+package detector
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+func TestDetectPII_Decision(t *testing.T) {
+	detector := New(createTestDataset())
+
+	tests := []struct {
+		name         string
+		words        []string
+		threshold    float64
+		passed       bool
+		reasonPrefix string
+		reasonPart   string
+	}{
+		{
+			name:         "Strong name passes",
+			words:        []string{"José", "Manuel", "García"},
+			threshold:    0.7,
+			passed:       true,
+			reasonPrefix: "Passed: ",
+			reasonPart:   ">= 0.70",
+		},
+		{
+			name:         "Unknown surname is rejected",
+			words:        []string{"Jose", "Xyzzy"},
+			threshold:    0.7,
+			passed:       false,
+			reasonPrefix: "Rejected: ",
+			reasonPart:   "1 token not found in their role",
+		},
+		{
+			name:         "Nothing matches",
+			words:        []string{"Xyzzy", "Qwerty"},
+			threshold:    0.7,
+			passed:       false,
+			reasonPrefix: "Rejected: ",
+			reasonPart:   "no token matched a known name",
+		},
+		{
+			name:         "Too few words",
+			words:        []string{"Jose"},
+			threshold:    0.5,
+			passed:       false,
+			reasonPrefix: "Rejected: ",
+			reasonPart:   "2 to 6 words",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := detector.DetectPIIWithThreshold(tt.words, tt.threshold)
+			decision := result.Decision
+
+			if decision.Passed != tt.passed || decision.Passed != result.IsLikelyName {
+				t.Errorf("Expected Passed=%v matching IsLikelyName=%v, got %v", tt.passed, result.IsLikelyName, decision.Passed)
+			}
+			if decision.Threshold != tt.threshold || decision.Confidence != result.Confidence {
+				t.Errorf("Expected threshold %.2f and confidence %.3f, got %.2f and %.3f",
+					tt.threshold, result.Confidence, decision.Threshold, decision.Confidence)
+			}
+			if !strings.HasPrefix(decision.Reason, tt.reasonPrefix) || !strings.Contains(decision.Reason, tt.reasonPart) {
+				t.Errorf("Expected reason starting with %q containing %q, got %q", tt.reasonPrefix, tt.reasonPart, decision.Reason)
+			}
+
+			for _, factor := range decision.Supporting {
+				if factor.Impact <= 0 {
+					t.Errorf("Supporting factor %s has non-positive impact %.3f", factor.Name, factor.Impact)
+				}
+			}
+			for _, factor := range decision.Detracting {
+				if factor.Impact >= 0 {
+					t.Errorf("Detracting factor %s has non-negative impact %.3f", factor.Name, factor.Impact)
+				}
+			}
+		})
+	}
+}
+
+func TestScorerFactors_SumToScore(t *testing.T) {
+	scorer := NewScorer(createTestDataset(), DefaultScoreConfig())
+
+	combos := []types.NameCombination{
+		{FirstNames: []string{"Jose", "Manuel"}, Surnames: []string{"Garcia", "Lopez"}},
+		{FirstNames: []string{"Jose"}, Surnames: []string{"Xyzzy"}},
+		{FirstNames: []string{"de"}, Surnames: []string{"Garcia"}},
+		{FirstNames: []string{"Xyzzy"}, Surnames: []string{"Qwerty"}},
+	}
+
+	for _, combo := range combos {
+		var sum float64
+		for _, factor := range scorer.Factors(combo) {
+			sum += factor.Impact
+		}
+		if raw := scorer.rawScore(combo); math.Abs(sum-raw) > 1e-9 {
+			t.Errorf("%v: factors sum to %.6f, expected the unclamped score %.6f", combo, sum, raw)
+		}
+	}
+}
+
+func TestDetectPII_DecisionFactorsSumToConfidence(t *testing.T) {
+	detector := New(createTestDataset())
+
+	inputs := [][]string{
+		{"José", "Manuel", "García"},
+		{"Jose", "Garcia", "Lopez"},
+		{"Jose", "Xyzzy"},
+		{"Maria", "de", "la", "Cruz"},
+		{"Xyzzy", "Qwerty"},
+	}
+
+	for _, words := range inputs {
+		result := detector.DetectPII(words)
+
+		var sum float64
+		for _, factor := range append(result.Decision.Supporting, result.Decision.Detracting...) {
+			sum += factor.Impact
+		}
+
+		// Without calibration the confidence is the factors' sum capped at 1
+		if expected := math.Min(sum, 1.0); math.Abs(expected-result.Confidence) > 1e-9 {
+			t.Errorf("%v: decision factors sum to %.6f, expected them to explain the confidence %.6f",
+				words, sum, result.Confidence)
+		}
+	}
+}
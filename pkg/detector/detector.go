@@ -1,36 +1,205 @@
 package detector
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
-	"unicode"
 
 	"github.com/montevive/go-name-detector/pkg/loader"
 	"github.com/montevive/go-name-detector/pkg/types"
 )
 
-// Detector handles PII name detection
+// ErrInvalidWordCount is returned by DetectPIIE for input with fewer words
+// than DetectorConfig.MinWords or more than MaxWords (2 and 6 by default)
+var ErrInvalidWordCount = errors.New("invalid word count for a name")
+
+// ErrInsufficientWords is returned by DetectPIIE when fewer words than a
+// name needs remain after cleaning (e.g. the rest were punctuation or stop
+// words). The returned error wraps it with the configured minimum.
+var ErrInsufficientWords = errors.New("too few words could be part of a name")
+
+// ErrUnknownName is returned by DumpName for a name found in neither role
+var ErrUnknownName = errors.New("name not found in the dataset")
+
+// DetectorConfig holds configuration for how the detector prepares input words
+// before they are scored
+type DetectorConfig struct {
+	// SurnamePrefixes maps a lowercase prefix to the separator used when it is
+	// joined with the following word to form a single surname token
+	// ("st." + "John" -> "St. John", "mc" + "Donald" -> "McDonald").
+	// Prefixes ending in a period or apostrophe always bind; bare-word
+	// prefixes ("saint", "mac") only bind when the composed surname is in the
+	// dataset, so "Mac Miller" keeps "Mac" as a first name.
+	// An empty map disables composition.
+	SurnamePrefixes map[string]string
+
+	// NormalizeOutput returns FirstNames and Surnames in Unicode NFC form so
+	// that decomposed (NFD) input is echoed back as composed characters
+	NormalizeOutput bool
+
+	// NormalizeCase title-cases FirstNames and Surnames for display, so
+	// "jose garcia" is reported as "Jose Garcia". Words already in mixed case
+	// ("McDonald") are kept, and surname particles ("de la Cruz") are
+	// lowercased.
+	NormalizeCase bool
+
+	// DetectUsernames makes a single username-like token ("jose.garcia",
+	// "jgarcia", "jose_garcia@example.com") be split into a first name and
+	// surname instead of being rejected. Recovered results use patterns
+	// prefixed with "username_", such as "username_initial_1_last".
+	DetectUsernames bool
+
+	// MaxCombinations caps the number of first name/surname splits scored per
+	// detection so long inputs can't consume unbounded CPU. When the cap is
+	// hit, the most balanced splits are kept and the result is flagged with
+	// CombinationsTruncated. Zero or negative disables the cap.
+	MaxCombinations int
+
+	// AllowSurnameFirst also tries splits with the surnames before the first
+	// names, as in "Garcia Jose" from forms that ask for the surname first.
+	// On a tie, the forward split wins unless the surname-first one has
+	// better-ranked first names. It is off by default, since it changes the
+	// scores and the reported split of input such as "Smith John".
+	AllowSurnameFirst bool
+
+	// MinWords and MaxWords bound the number of input words analyzed; other
+	// input is rejected with the "invalid_length" pattern. A MinWords of 1
+	// scores single-word mononyms as a lone first name or surname. Zero or
+	// negative uses the defaults of 2 and 6. Input of n words is scored as
+	// n-1 splits, or 2(n-1) with AllowSurnameFirst, each costing a lookup per
+	// word, so detection time grows with the square of the word count until
+	// MaxCombinations caps the splits.
+	MinWords int
+	MaxWords int
+
+	// DetectMononyms scores a single word ("Mohammed", "García") by its rank
+	// as a first name or a surname, whichever fits better, reporting the
+	// "1_mononym" pattern. It lowers MinWords to 1 and leaves longer input
+	// unaffected.
+	DetectMononyms bool
+
+	// ReportAmbiguousTokens fills Details.AmbiguousTokens with the tokens of
+	// the winning combination that exist as both a first name and a surname
+	ReportAmbiguousTokens bool
+
+	// Explain fills Details.ScoreBreakdown with the delta each scoring step
+	// contributed to the confidence, for tuning and debugging thresholds
+	Explain bool
+
+	// CacheSize bounds an LRU cache of DetectPII results keyed by input words
+	// and threshold, so services seeing repeated inputs skip rescoring them.
+	// The least recently used result is evicted once the cache is full, and
+	// hits, evictions and size are reported by Detector.CacheStats. Cached
+	// results share their Details slices, so callers must not modify them in
+	// place. Zero or negative disables the cache.
+	CacheSize int
+
+	// GenericSurnameRank, when positive, keeps a detection from being flagged
+	// when no first name matched and every matched surname ranks at or better
+	// than this somewhere, so a lone "Smith" next to an unknown word needs a
+	// first name or a more distinctive surname to corroborate it. Zero
+	// disables the rule.
+	GenericSurnameRank int32
+}
+
+// DefaultDetectorConfig returns the default detector configuration
+func DefaultDetectorConfig() DetectorConfig {
+	return DetectorConfig{
+		SurnamePrefixes: map[string]string{
+			"st.":   " ",
+			"saint": " ",
+			"mc":    "",
+			"mac":   "",
+			"o'":    "",
+		},
+		MaxCombinations: 64,
+		MinWords:        defaultMinWords,
+		MaxWords:        defaultMaxWords,
+	}
+}
+
+// Default word count limits for DetectorConfig.MinWords and MaxWords
+const (
+	defaultMinWords = 2
+	defaultMaxWords = 6
+)
+
+// Name orders reported in NameDetails.Order
+const (
+	orderWestern = "western"
+	orderEastern = "eastern"
+)
+
+// detailTopCountries is the number of countries reported in
+// NameDetails.TopCountries
+const detailTopCountries = 5
+
+// wordLimits returns the configured MinWords and MaxWords, falling back to
+// the defaults for unset values and allowing single words for mononyms
+func (c DetectorConfig) wordLimits() (int, int) {
+	minWords, maxWords := c.MinWords, c.MaxWords
+	if minWords <= 0 {
+		minWords = defaultMinWords
+	}
+	if c.DetectMononyms {
+		minWords = 1
+	}
+	if maxWords <= 0 {
+		maxWords = defaultMaxWords
+	}
+	return minWords, maxWords
+}
+
+// acceptsWordCount reports whether input of n words is analyzed rather than
+// rejected for its length: a single word when usernames are detected, or
+// between the configured MinWords and MaxWords
+func (d *Detector) acceptsWordCount(n int) bool {
+	if d.config.DetectUsernames && n == 1 {
+		return true
+	}
+	minWords, maxWords := d.config.wordLimits()
+	return n >= minWords && n <= maxWords
+}
+
+// Detector handles PII name detection. A Detector is safe for concurrent use
+// by multiple goroutines once created: its configuration and dataset are only
+// read, the result cache is guarded by a lock, and SetDataset swaps the
+// dataset atomically. The dataset itself must not be modified after it is
+// passed to the detector.
 type Detector struct {
 	scorer *Scorer
+	config DetectorConfig
+	cache  *resultCache // nil when caching is disabled
 }
 
 // New creates a new Detector with the given dataset
 func New(dataset *types.NameDataset) *Detector {
-	config := DefaultScoreConfig()
-	scorer := NewScorer(dataset, config)
-	
-	return &Detector{
-		scorer: scorer,
-	}
+	return NewWithDetectorConfig(dataset, DefaultScoreConfig(), DefaultDetectorConfig())
 }
 
 // NewWithConfig creates a new Detector with custom scoring configuration
 func NewWithConfig(dataset *types.NameDataset, config ScoreConfig) *Detector {
+	return NewWithDetectorConfig(dataset, config, DefaultDetectorConfig())
+}
+
+// NewWithDetectorConfig creates a new Detector with custom scoring and input
+// handling configuration
+func NewWithDetectorConfig(dataset *types.NameDataset, config ScoreConfig, detectorConfig DetectorConfig) *Detector {
 	scorer := NewScorer(dataset, config)
-	
-	return &Detector{
+
+	d := &Detector{
 		scorer: scorer,
+		config: detectorConfig,
+	}
+	if detectorConfig.CacheSize > 0 {
+		d.cache = newResultCache(detectorConfig.CacheSize)
 	}
+
+	return d
 }
 
 // NewDefault creates a new Detector with embedded dataset - ready to use out of the box
@@ -43,67 +212,307 @@ func NewDefault() (*Detector, error) {
 	return New(l.GetDataset()), nil
 }
 
+// SetDataset points the detector at a new dataset, such as one reloaded with
+// Loader.Reload, without recreating it. It is safe to call while detections
+// are running: each dataset lookup sees either the old or the new dataset in
+// full, so only a detection that straddles the swap can mix the two. The
+// result cache, if enabled, is cleared and results scored before the swap are
+// not added to it. The new dataset must not be modified afterwards.
+func (d *Detector) SetDataset(dataset *types.NameDataset) {
+	d.scorer.SetDataset(dataset)
+	if d.cache != nil {
+		d.cache.clear()
+	}
+}
+
 // DetectPII analyzes words to determine if they represent a PII name
 func (d *Detector) DetectPII(words []string) types.PIIResult {
 	return d.DetectPIIWithThreshold(words, 0.7) // Default threshold
 }
 
+// DetectPIIE is DetectPIIWithThreshold for callers that need to tell input
+// that could not be analyzed apart from input that is not a name. It returns
+// ErrInvalidWordCount or ErrInsufficientWords, along with the rejected
+// result, when the words could not be scored.
+func (d *Detector) DetectPIIE(words []string, threshold float64) (types.PIIResult, error) {
+	result := d.DetectPIIWithThreshold(words, threshold)
+	if result.Analyzed {
+		return result, nil
+	}
+
+	minWords, maxWords := d.config.wordLimits()
+	switch result.Details.Pattern {
+	case "insufficient_words":
+		return result, fmt.Errorf("%w: want at least %d", ErrInsufficientWords, minCleanWords(minWords))
+	default:
+		return result, fmt.Errorf("%w: got %d, want %d to %d", ErrInvalidWordCount, len(words), minWords, maxWords)
+	}
+}
+
 // DetectPIIWithThreshold analyzes words with a custom confidence threshold
 func (d *Detector) DetectPIIWithThreshold(words []string, threshold float64) types.PIIResult {
-	if len(words) < 2 || len(words) > 6 {
-		return types.PIIResult{
-			IsLikelyName: false,
-			Confidence:   0.0,
-			Details: types.NameDetails{
-				Pattern: "invalid_length",
-			},
-		}
+	if d.cache == nil {
+		return d.detect(words, threshold, nil)
+	}
+
+	key := newCacheKey(words, threshold)
+	result, epoch, ok := d.cache.get(key)
+	if ok {
+		return result
 	}
+	result = d.detect(words, threshold, nil)
+	d.cache.put(key, result, epoch)
+	return result
+}
+
+// detect runs detection, adjusting the winning combination's score by the
+// given hints when they are non-nil
+func (d *Detector) detect(words []string, threshold float64, hints *DetectionHints) types.PIIResult {
+	// A background context is never canceled, so detection can't fail
+	result, _ := d.detectContext(context.Background(), words, threshold, hints)
+	return result
+}
 
-	// Clean and normalize words
-	cleanWords := d.cleanWords(words)
-	if len(cleanWords) < 2 {
-		return types.PIIResult{
-			IsLikelyName: false,
-			Confidence:   0.0,
-			Details: types.NameDetails{
-				Pattern: "insufficient_words",
-			},
+// detectContext is detect, returning ErrCanceled once ctx is done
+func (d *Detector) detectContext(ctx context.Context, words []string, threshold float64, hints *DetectionHints) (types.PIIResult, error) {
+	if err := canceled(ctx); err != nil {
+		return types.PIIResult{}, err
+	}
+
+	if d.config.DetectUsernames && len(words) == 1 {
+		if result, ok, err := d.detectUsername(ctx, words[0], threshold, hints); err != nil || ok {
+			return result, err
 		}
 	}
 
+	minWords, maxWords := d.config.wordLimits()
+	if len(words) < minWords || len(words) > maxWords {
+		result := rejectedResult("invalid_length", threshold)
+		result.Decision.Reason = fmt.Sprintf("Rejected: a name needs %d to %d words", minWords, maxWords)
+		return result, nil
+	}
+
+	// Clean and normalize words, then bind surname prefixes to their surname
+	cleanWords, positions := d.cleanWords(words)
+	cleanWords, positions, composed := d.composePrefixes(cleanWords, positions)
+	if required := minCleanWords(minWords); len(cleanWords) < required {
+		result := rejectedResult("insufficient_words", threshold)
+		result.Decision.Reason = fmt.Sprintf("Rejected: a name needs at least %s besides punctuation and stop words", countNoun(required, "word"))
+		return result, nil
+	}
+
+	return d.detectWords(ctx, cleanWords, positions, composed, threshold, hints)
+}
+
+// minCleanWords is the number of words that must remain after cleaning for
+// input to be scored: two, or one when minWords allows mononyms
+func minCleanWords(minWords int) int {
+	return min(minWords, 2)
+}
+
+// detectWords scores every split of cleaned words, whose input positions are
+// given by positions, and builds the result of the best one
+func (d *Detector) detectWords(ctx context.Context, words []string, positions [][]int, composed []types.ComposedToken, threshold float64, hints *DetectionHints) (types.PIIResult, error) {
 	// Generate all possible name combinations
-	combinations := d.generateCombinations(cleanWords)
+	combinations := d.generateCombinations(words)
+	combinations, truncated := d.limitCombinations(combinations)
 	
 	// Score each combination and find the best one
-	bestCombo, bestScore := d.findBestCombination(combinations)
+	bestCombo, bestScore, err := d.findBestCombination(ctx, combinations)
+	if err != nil {
+		return types.PIIResult{}, err
+	}
+	factors := d.scorer.Factors(bestCombo)
+
+	// Fuse external hints into the score of the winning combination
+	if hints != nil {
+		hintFactors := d.scorer.hintFactors(bestCombo, *hints)
+		if len(hintFactors) > 0 {
+			bestScore = d.scorer.rawScore(bestCombo)
+			for _, factor := range hintFactors {
+				bestScore += factor.Impact
+			}
+			bestScore = math.Max(0.0, math.Min(1.0, bestScore))
+			factors = append(factors, hintFactors...)
+		}
+	}
 
-	// Determine if it's likely a name
-	isLikelyName := bestScore >= threshold
+	return d.buildResult(scoredResult{
+		combo:     bestCombo,
+		score:     d.scorer.config.Calibration.Apply(bestScore),
+		factors:   factors,
+		tokens:    words,
+		positions: positions,
+		composed:  composed,
+		truncated: truncated,
+	}, threshold), nil
+}
+
+// scoredResult is a winning combination and what buildResult needs to
+// describe it
+type scoredResult struct {
+	combo     types.NameCombination
+	score     float64        // Final confidence, after hints and calibration
+	factors   []types.Factor // Explain score in the decision
+	tokens    []string       // Cleaned input words, classified as rare or unknown
+	positions [][]int        // Input positions of each token; nil leaves indices unset
+	composed  []types.ComposedToken
+	truncated bool // MaxCombinations dropped some splits
+}
+
+// buildResult assembles the result of a detection from its winning
+// combination: the names and their input indices, match, gender and country
+// details, and the decision at threshold. Every detection path builds its
+// result here so they report the same details.
+func (d *Detector) buildResult(scored scoredResult, threshold float64) types.PIIResult {
+	bestCombo := scored.combo
 
 	// Build result details
 	pattern := d.buildPattern(bestCombo)
 	topCountry := d.scorer.GetTopCountry(bestCombo)
-	gender := d.scorer.GetGender(bestCombo)
+	gender, genderConfidence := d.scorer.PredictGender(bestCombo)
+
+	firstNames, surnames := bestCombo.FirstNames, bestCombo.Surnames
+	var firstIndices, surnameIndices []int
+	if positions := scored.positions; positions != nil {
+		firstPositions := positions[:len(firstNames)]
+		surnamePositions := positions[len(firstNames) : len(firstNames)+len(surnames)]
+		if bestCombo.Reversed {
+			surnamePositions = positions[:len(surnames)]
+			firstPositions = positions[len(surnames) : len(surnames)+len(firstNames)]
+		}
+		firstIndices = flattenPositions(firstPositions)
+		surnameIndices = flattenPositions(surnamePositions)
+	}
+	matchedFirst, matchedSurnames := d.scorer.MatchedNames(bestCombo)
+	if d.config.NormalizeOutput {
+		firstNames = normalizeTokens(firstNames)
+		surnames = normalizeTokens(surnames)
+		matchedFirst = normalizeTokens(matchedFirst)
+		matchedSurnames = normalizeTokens(matchedSurnames)
+	}
+	firstNames, surnames = d.caseNames(firstNames, surnames)
+
+	rare, unknown := d.scorer.ClassifyTokens(scored.tokens)
+	roleFit := d.scorer.RoleFit(bestCombo)
+	firstConfidence, surnameConfidence := d.scorer.SideConfidences(bestCombo)
+
+	var ambiguous []string
+	if d.config.ReportAmbiguousTokens {
+		ambiguous = d.scorer.AmbiguousTokens(bestCombo)
+	}
+
+	var breakdown []types.ScoreTerm
+	if d.config.Explain {
+		breakdown = d.scorer.ScoreBreakdown(bestCombo, scored.factors)
+	}
 
-	return types.PIIResult{
-		IsLikelyName: isLikelyName,
-		Confidence:   bestScore,
+	result := types.PIIResult{
+		IsLikelyName: scored.score >= threshold,
+		Confidence:   scored.score,
+		Analyzed:     true,
 		Details: types.NameDetails{
-			FirstNames: bestCombo.FirstNames,
-			Surnames:   bestCombo.Surnames,
+			FirstNames: firstNames,
+			Surnames:   surnames,
 			Pattern:    pattern,
+			Order:      nameOrder(bestCombo),
 			TopCountry: topCountry,
 			Gender:     gender,
+			RoleFit:    roleFit,
+
+			GenderConfidence: genderConfidence,
+			TopCountryName:   CountryName(topCountry),
+
+			CountryDistribution: d.scorer.CountryDistribution(bestCombo),
+			TopCountries:        d.scorer.GetTopCountries(bestCombo, detailTopCountries),
+
+			MatchedFirstNames: matchedFirst,
+			MatchedSurnames:   matchedSurnames,
+
+			Matches: d.scorer.MatchDetails(bestCombo),
+
+			FirstNameConfidence: firstConfidence,
+			SurnameConfidence:   surnameConfidence,
+
+			OriginUnknown: d.scorer.OriginUnknown(bestCombo),
+
+			FirstNameIndices: firstIndices,
+			SurnameIndices:   surnameIndices,
+			ComposedTokens:   scored.composed,
+
+			CombinationsTruncated: scored.truncated,
+			AmbiguousTokens:       ambiguous,
+			ScoreBreakdown:        breakdown,
+
+			RareTokens:       rare,
+			UnknownTokens:    unknown,
+			HasRareMatches:   len(rare) > 0,
+			HasUnknownTokens: len(unknown) > 0,
 		},
 	}
+	result.Decision = buildDecision(result, threshold, scored.factors)
+
+	if d.config.GenericSurnameRank > 0 && result.IsLikelyName {
+		if surname, generic := d.scorer.GenericSurnameOnly(bestCombo, d.config.GenericSurnameRank); generic {
+			result.IsLikelyName = false
+			result.Decision.Passed = false
+			result.Decision.Reason = fmt.Sprintf("Rejected: only evidence is the common surname %q (top %d) with no first name match (%.2f >= %.2f)",
+				surname, d.config.GenericSurnameRank, result.Confidence, threshold)
+		}
+	}
+
+	return result
+}
+
+// rejectedResult returns the result for input that can't be a name at all,
+// with the given pattern describing why
+func rejectedResult(pattern string, threshold float64) types.PIIResult {
+	result := types.PIIResult{
+		IsLikelyName: false,
+		Confidence:   0.0,
+		Details: types.NameDetails{
+			Pattern: pattern,
+		},
+	}
+	result.Decision = buildDecision(result, threshold, nil)
+	return result
+}
+
+// ClassifyAtThresholds scores words once and reports the decision at each of
+// the given thresholds, keyed by threshold
+func (d *Detector) ClassifyAtThresholds(words []string, thresholds []float64) map[float64]bool {
+	result := d.DetectPIIWithThreshold(words, 0.0)
+
+	decisions := make(map[float64]bool, len(thresholds))
+	for _, threshold := range thresholds {
+		decisions[threshold] = result.Analyzed && result.Confidence >= threshold
+	}
+
+	return decisions
 }
 
-// cleanWords removes empty strings, trims whitespace, and filters invalid words
-func (d *Detector) cleanWords(words []string) []string {
+// SeparatingThreshold returns a threshold at which positive is classified as
+// a name and negative is not, halfway between their confidences. It returns
+// false when no such threshold exists because negative scores at least as
+// high as positive.
+func (d *Detector) SeparatingThreshold(positive, negative []string) (float64, bool) {
+	positiveScore := d.DetectPIIWithThreshold(positive, 0.0).Confidence
+	negativeScore := d.DetectPIIWithThreshold(negative, 0.0).Confidence
+
+	if negativeScore >= positiveScore {
+		return 0, false
+	}
+
+	return (positiveScore + negativeScore) / 2, true
+}
+
+// cleanWords removes empty strings, trims whitespace, and filters invalid words.
+// It also returns, for each cleaned word, its position in the input slice.
+func (d *Detector) cleanWords(words []string) ([]string, [][]int) {
 	var cleaned []string
+	var positions [][]int
 	
-	for _, word := range words {
+	for i, word := range words {
 		word = strings.TrimSpace(word)
 		if len(word) == 0 {
 			continue
@@ -112,10 +521,69 @@ func (d *Detector) cleanWords(words []string) []string {
 		// Skip words that are clearly not names (too short, numbers, special chars)
 		if d.isValidNameWord(word) {
 			cleaned = append(cleaned, word)
+			positions = append(positions, []int{i})
 		}
 	}
 	
-	return cleaned
+	return cleaned, positions
+}
+
+// composePrefixes joins surname prefixes ("St.", "Mc", "O'") with the word
+// that follows them so the pair is scored as a single surname. The input
+// positions of joined words are merged to stay aligned with the result.
+func (d *Detector) composePrefixes(words []string, positions [][]int) ([]string, [][]int, []types.ComposedToken) {
+	if len(d.config.SurnamePrefixes) == 0 {
+		return words, positions, nil
+	}
+
+	var result []string
+	var resultPositions [][]int
+	var composed []types.ComposedToken
+
+	for i := 0; i < len(words); i++ {
+		separator, isPrefix := d.config.SurnamePrefixes[d.scorer.profile.toLower(words[i])]
+		if !isPrefix || i+1 >= len(words) {
+			result = append(result, words[i])
+			resultPositions = append(resultPositions, positions[i])
+			continue
+		}
+
+		token := words[i] + separator + words[i+1]
+		if !strings.HasSuffix(words[i], ".") && !strings.HasSuffix(words[i], "'") {
+			// Bare-word prefixes are ordinary names too; only bind them when
+			// the dataset knows the composed surname
+			if _, exists := d.scorer.lookup(token, false); !exists {
+				result = append(result, words[i])
+				resultPositions = append(resultPositions, positions[i])
+				continue
+			}
+		}
+
+		result = append(result, token)
+		resultPositions = append(resultPositions, append(append([]int{}, positions[i]...), positions[i+1]...))
+		composed = append(composed, types.ComposedToken{
+			Token: token,
+			Parts: []string{words[i], words[i+1]},
+		})
+		i++
+	}
+
+	return result, resultPositions, composed
+}
+
+// flattenPositions concatenates the input positions of a run of tokens.
+// Tokens split from the same input word, as usernames are, share its
+// position, which is reported once.
+func flattenPositions(positions [][]int) []int {
+	var flat []int
+	for _, p := range positions {
+		for _, position := range p {
+			if len(flat) == 0 || flat[len(flat)-1] != position {
+				flat = append(flat, position)
+			}
+		}
+	}
+	return flat
 }
 
 // isValidNameWord checks if a word could plausibly be part of a name
@@ -126,55 +594,28 @@ func (d *Detector) isValidNameWord(word string) bool {
 	}
 	
 	// Must contain only letters (and possibly hyphens, apostrophes, dots)
+	// as allowed by the locale profile
 	for _, r := range word {
-		if !(unicode.IsLetter(r) || r == '-' || r == '\'' || r == '.') {
+		if !d.scorer.profile.isValidRune(r) {
 			return false
 		}
 	}
 	
 	// Skip common non-name words
-	lowerWord := strings.ToLower(word)
-	commonWords := map[string]bool{
-		"the": true, "and": true, "or": true, "but": true, "in": true, "on": true,
-		"at": true, "to": true, "for": true, "of": true, "with": true, "by": true,
-		"is": true, "are": true, "was": true, "were": true, "be": true, "been": true,
-		"have": true, "has": true, "had": true, "do": true, "does": true, "did": true,
-		"will": true, "would": true, "could": true, "should": true, "may": true, "might": true,
-		"can": true, "must": true, "shall": true, "this": true, "that": true, "these": true,
-		"those": true, "a": true, "an": true, "it": true, "he": true, "she": true,
-		"they": true, "we": true, "you": true, "i": true, "me": true, "him": true,
-		"her": true, "them": true, "us": true, "my": true, "your": true, "his": true,
-		"our": true, "their": true, "its": true,
-	}
-	
-	return !commonWords[lowerWord]
-}
-
-// isProbablyPreposition checks if a word is likely a preposition/connector
-// that shouldn't be counted as a first or last name
-func (d *Detector) isProbablyPreposition(word string) bool {
-	lowerWord := strings.ToLower(word)
-	// Common prepositions/connectors in multiple languages
-	prepositions := map[string]bool{
-		// Spanish
-		"de": true, "del": true, "la": true, "el": true,
-		"los": true, "las": true, "y": true,
-		// Portuguese
-		"da": true, "do": true, "dos": true, "das": true,
-		// French
-		"du": true, "le": true, "les": true,
-		// Dutch/German
-		"van": true, "von": true, "der": true, "den": true,
-		// English
-		"of": true, "and": true,
-	}
-	
-	return prepositions[lowerWord]
+	return !d.scorer.config.StopWords[d.scorer.profile.toLower(word)]
 }
 
 // generateCombinations creates all possible splits of words into first names and surnames
 func (d *Detector) generateCombinations(words []string) []types.NameCombination {
 	var combinations []types.NameCombination
+
+	// A single word is scored as a lone first name and as a lone surname
+	if len(words) == 1 {
+		return []types.NameCombination{
+			{FirstNames: words},
+			{Surnames: words},
+		}
+	}
 	
 	// Try all possible splits where at least 1 word is first name and 1 is surname
 	for i := 1; i < len(words); i++ {
@@ -184,44 +625,226 @@ func (d *Detector) generateCombinations(words []string) []types.NameCombination
 		}
 		combinations = append(combinations, combo)
 	}
+
+	// Then the same splits with the surnames written first
+	if d.config.AllowSurnameFirst {
+		for i := 1; i < len(words); i++ {
+			combinations = append(combinations, types.NameCombination{
+				FirstNames: words[i:],
+				Surnames:   words[:i],
+				Reversed:   true,
+			})
+		}
+	}
 	
 	return combinations
 }
 
-// findBestCombination scores all combinations and returns the best one
-func (d *Detector) findBestCombination(combinations []types.NameCombination) (types.NameCombination, float64) {
+// limitCombinations enforces MaxCombinations. Splits are kept in order of
+// balance (smallest difference between first name and surname counts, then
+// fewest first names), so the result is deterministic for a given input.
+func (d *Detector) limitCombinations(combinations []types.NameCombination) ([]types.NameCombination, bool) {
+	limit := d.config.MaxCombinations
+	if limit <= 0 || len(combinations) <= limit {
+		return combinations, false
+	}
+
+	kept := make([]types.NameCombination, len(combinations))
+	copy(kept, combinations)
+	sort.SliceStable(kept, func(i, j int) bool {
+		bi := imbalance(kept[i])
+		bj := imbalance(kept[j])
+		if bi != bj {
+			return bi < bj
+		}
+		return len(kept[i].FirstNames) < len(kept[j].FirstNames)
+	})
+
+	return kept[:limit], true
+}
+
+// imbalance returns the difference between the first name and surname counts
+func imbalance(combo types.NameCombination) int {
+	diff := len(combo.FirstNames) - len(combo.Surnames)
+	if diff < 0 {
+		return -diff
+	}
+	return diff
+}
+
+// scoreTieEpsilon is how close two combination scores must be for the
+// tie-breaking rules of findBestCombination to decide between them
+const scoreTieEpsilon = 1e-9
+
+// findBestCombination scores all combinations and returns the best one. It
+// returns ErrCanceled if ctx is done before every combination is scored.
+func (d *Detector) findBestCombination(ctx context.Context, combinations []types.NameCombination) (types.NameCombination, float64, error) {
 	var bestCombo types.NameCombination
 	var bestScore float64
 	
 	for _, combo := range combinations {
-		score := d.scorer.ScoreCombination(combo)
-		if score > bestScore {
+		if err := canceled(ctx); err != nil {
+			return types.NameCombination{}, 0, err
+		}
+
+		score := d.scorer.clampedScore(combo)
+		switch {
+		case score > bestScore+scoreTieEpsilon:
+			bestScore = score
+			bestCombo = combo
+		case bestScore > 0 && score >= bestScore-scoreTieEpsilon && d.scorer.breaksTie(combo, bestCombo):
 			bestScore = score
 			bestCombo = combo
 		}
 	}
 	
-	return bestCombo, bestScore
+	return bestCombo, bestScore, nil
 }
 
 // buildPattern creates a pattern string describing the name structure
 func (d *Detector) buildPattern(combo types.NameCombination) string {
 	firstCount := len(combo.FirstNames)
 	lastCount := len(combo.Surnames)
+	if firstCount+lastCount == 1 {
+		return "1_mononym"
+	}
 	
 	return fmt.Sprintf("%d_first_%d_last", firstCount, lastCount)
 }
 
+// nameOrder returns whether combo reads first names first ("western") or
+// surnames first ("eastern"), or "" for a mononym
+func nameOrder(combo types.NameCombination) string {
+	switch {
+	case len(combo.FirstNames)+len(combo.Surnames) < 2:
+		return ""
+	case combo.Reversed:
+		return orderEastern
+	default:
+		return orderWestern
+	}
+}
+
+// LookupFirstName returns the dataset entry for name as a first name, using
+// the same exact, accent-normalized and punctuation-stripped lookup as
+// scoring, so "García" and "Garcia" find the same entry. The returned data is
+// shared with the dataset and must not be modified.
+func (d *Detector) LookupFirstName(name string) (*types.NameData, bool) {
+	return d.scorer.lookup(strings.TrimSpace(name), true)
+}
+
+// LookupSurname returns the dataset entry for name as a surname, using the
+// same lookup as LookupFirstName
+func (d *Detector) LookupSurname(name string) (*types.NameData, bool) {
+	return d.scorer.lookup(strings.TrimSpace(name), false)
+}
+
+// Lookup returns the dataset entries for name as a first name and as a
+// surname, each nil when the name isn't known in that role, for heuristics
+// that weigh both roles of a token
+func (d *Detector) Lookup(name string) (firstData, lastData *types.NameData) {
+	firstData, _ = d.LookupFirstName(name)
+	lastData, _ = d.LookupSurname(name)
+	return firstData, lastData
+}
+
+// nameDump is the JSON form of a name's dataset entries written by DumpName
+type nameDump struct {
+	Name      string        `json:"name"`
+	FirstName *nameDataDump `json:"first_name"` // Null when not a known first name
+	Surname   *nameDataDump `json:"surname"`    // Null when not a known surname
+}
+
+// nameDataDump is the JSON form of a single NameData entry
+type nameDataDump struct {
+	Name    string             `json:"name,omitempty"` // Spelling in the source data
+	Country map[string]float32 `json:"country,omitempty"`
+	Gender  map[string]float32 `json:"gender,omitempty"`
+	Rank    map[string]int32   `json:"rank,omitempty"`
+	MinRank int32              `json:"min_rank"` // Best rank across countries, 0 when the entry has none
+	Aliases []string           `json:"aliases,omitempty"`
+}
+
+// DumpName returns the dataset entries for name in both roles as indented
+// JSON, for inspecting why a name does or doesn't match. It returns
+// ErrUnknownName when the name is found in neither role.
+func (d *Detector) DumpName(name string) ([]byte, error) {
+	firstData, lastData := d.Lookup(name)
+	if firstData == nil && lastData == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownName, name)
+	}
+
+	return json.MarshalIndent(nameDump{
+		Name:      strings.TrimSpace(name),
+		FirstName: dumpNameData(firstData),
+		Surname:   dumpNameData(lastData),
+	}, "", "  ")
+}
+
+// dumpNameData converts a dataset entry to its JSON form, nil for nil
+func dumpNameData(data *types.NameData) *nameDataDump {
+	if data == nil {
+		return nil
+	}
+	return &nameDataDump{
+		Name:    data.Name,
+		Country: data.Country,
+		Gender:  data.Gender,
+		Rank:    data.Rank,
+		MinRank: minRankOf(data),
+		Aliases: data.Aliases,
+	}
+}
+
+// CoverageReport reports which fraction of the given tokens are present in the
+// dataset, to judge whether it is adequate for a corpus before tuning thresholds
+func (d *Detector) CoverageReport(tokens []string) types.CoverageReport {
+	var total, firstCount, lastCount, eitherCount int
+
+	for _, token := range tokens {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		total++
+
+		_, isFirst := d.scorer.lookup(token, true)
+		_, isLast := d.scorer.lookup(token, false)
+		if isFirst {
+			firstCount++
+		}
+		if isLast {
+			lastCount++
+		}
+		if isFirst || isLast {
+			eitherCount++
+		}
+	}
+
+	if total == 0 {
+		return types.CoverageReport{}
+	}
+
+	return types.CoverageReport{
+		Tokens:     total,
+		FirstNames: float64(firstCount) / float64(total),
+		Surnames:   float64(lastCount) / float64(total),
+		Either:     float64(eitherCount) / float64(total),
+		Neither:    float64(total-eitherCount) / float64(total),
+	}
+}
+
 // GetDatasetStats returns statistics about the loaded dataset
 func (d *Detector) GetDatasetStats() map[string]interface{} {
-	if d.scorer == nil || d.scorer.dataset == nil {
+	if d.scorer == nil || d.scorer.dataset.Load() == nil {
 		return map[string]interface{}{
 			"error": "dataset not loaded",
 		}
 	}
-	
+
+	dataset := d.scorer.dataset.Load()
 	return map[string]interface{}{
-		"first_names_count": len(d.scorer.dataset.FirstNames),
-		"last_names_count":  len(d.scorer.dataset.LastNames),
+		"first_names_count": len(dataset.FirstNames),
+		"last_names_count":  len(dataset.LastNames),
 	}
 }
\ No newline at end of file
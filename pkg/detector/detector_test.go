@@ -1,8 +1,14 @@
 package detector
 
 import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/montevive/go-name-detector/pkg/loader"
 	"github.com/montevive/go-name-detector/pkg/types"
 )
 
@@ -307,6 +313,1003 @@ func TestDetectPII_Thresholds(t *testing.T) {
 	}
 }
 
+func TestClassifyAtThresholds(t *testing.T) {
+	dataset := createTestDataset()
+	detector := New(dataset)
+
+	thresholds := []float64{0.0, 0.3, 0.5, 0.7, 0.9, 1.1}
+
+	for _, words := range [][]string{
+		{"Jose", "Garcia"},
+		{"Xyzzy", "Qwerty"},
+		{"Jose"},
+	} {
+		decisions := detector.ClassifyAtThresholds(words, thresholds)
+		if len(decisions) != len(thresholds) {
+			t.Fatalf("Expected %d decisions for %v, got %d", len(thresholds), words, len(decisions))
+		}
+
+		for _, threshold := range thresholds {
+			expected := detector.DetectPIIWithThreshold(words, threshold).IsLikelyName
+			if decisions[threshold] != expected {
+				t.Errorf("%v at threshold %v: expected %v, got %v", words, threshold, expected, decisions[threshold])
+			}
+		}
+	}
+}
+
+func TestSeparatingThreshold(t *testing.T) {
+	detector := New(createTestDataset())
+	name := []string{"Jose", "Garcia"}
+	nonName := []string{"Xyzzy", "Qwerty"}
+
+	threshold, ok := detector.SeparatingThreshold(name, nonName)
+	if !ok {
+		t.Fatalf("Expected a separating threshold between %v and %v", name, nonName)
+	}
+	if !detector.DetectPIIWithThreshold(name, threshold).IsLikelyName {
+		t.Errorf("Expected %v to be a name at threshold %.3f", name, threshold)
+	}
+	if detector.DetectPIIWithThreshold(nonName, threshold).IsLikelyName {
+		t.Errorf("Expected %v not to be a name at threshold %.3f", nonName, threshold)
+	}
+
+	if _, ok := detector.SeparatingThreshold(nonName, name); ok {
+		t.Error("Expected no separating threshold when the non-name outscores the name")
+	}
+	if _, ok := detector.SeparatingThreshold(name, name); ok {
+		t.Error("Expected no separating threshold for equal scores")
+	}
+}
+
+func TestDetectPII_SurnamePrefixes(t *testing.T) {
+	dataset := createTestDataset()
+	dataset.LastNames["ST JOHN"] = &types.NameData{
+		Country: map[string]float32{"GB": 0.4, "US": 0.3},
+		Rank:    map[string]int32{"GB": 90, "US": 361},
+	}
+	dataset.LastNames["MCDONALD"] = &types.NameData{
+		Country: map[string]float32{"GB": 0.3, "US": 0.4},
+		Rank:    map[string]int32{"GB": 40, "US": 65},
+	}
+	dataset.LastNames["OBRIEN"] = &types.NameData{
+		Country: map[string]float32{"IE": 0.5, "US": 0.2},
+		Rank:    map[string]int32{"IE": 7, "US": 120},
+	}
+
+	tests := []struct {
+		name         string
+		words        []string
+		expectedLast []string
+		composed     bool
+	}{
+		{"Abbreviated saint", []string{"John", "St.", "John"}, []string{"St. John"}, true},
+		{"Mc prefix", []string{"John", "Mc", "Donald"}, []string{"McDonald"}, true},
+		{"Apostrophe prefix", []string{"John", "O'", "Brien"}, []string{"O'Brien"}, true},
+		{"Bare prefix without dataset match", []string{"Mac", "Smith"}, []string{"Smith"}, false},
+	}
+
+	detector := New(dataset)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := detector.DetectPIIWithThreshold(tt.words, 0.5)
+
+			if !equalStringSlices(result.Details.Surnames, tt.expectedLast) {
+				t.Errorf("Expected surnames %v, got %v", tt.expectedLast, result.Details.Surnames)
+			}
+			if tt.composed != (len(result.Details.ComposedTokens) == 1) {
+				t.Errorf("Expected composed=%v, got %v", tt.composed, result.Details.ComposedTokens)
+			}
+			if tt.composed && !result.IsLikelyName {
+				t.Errorf("Expected composed surname to be detected (confidence: %.3f)", result.Confidence)
+			}
+		})
+	}
+
+	// Composition can be disabled with an empty prefix set
+	config := DefaultDetectorConfig()
+	config.SurnamePrefixes = nil
+	plain := NewWithDetectorConfig(dataset, DefaultScoreConfig(), config)
+	result := plain.DetectPIIWithThreshold([]string{"John", "Mc", "Donald"}, 0.5)
+	if len(result.Details.ComposedTokens) != 0 {
+		t.Errorf("Expected no composition when disabled, got %v", result.Details.ComposedTokens)
+	}
+}
+
+func TestDetectPII_CompoundNames(t *testing.T) {
+	dataset := createTestDataset()
+	dataset.FirstNames["ANNE"] = &types.NameData{
+		Country: map[string]float32{"GB": 0.3, "FR": 0.2},
+		Gender:  map[string]float32{"F": 1.0},
+		Rank:    map[string]int32{"GB": 30, "FR": 12},
+	}
+	dataset.FirstNames["MARIE"] = &types.NameData{
+		Country: map[string]float32{"FR": 0.4},
+		Gender:  map[string]float32{"F": 1.0},
+		Rank:    map[string]int32{"FR": 2},
+	}
+	dataset.FirstNames["LLOYD"] = &types.NameData{
+		Country: map[string]float32{"GB": 0.2},
+		Gender:  map[string]float32{"M": 1.0},
+		Rank:    map[string]int32{"GB": 400},
+	}
+	dataset.LastNames["LLOYD"] = &types.NameData{
+		Country: map[string]float32{"GB": 0.3},
+		Rank:    map[string]int32{"GB": 60},
+	}
+	dataset.LastNames["WEBBER"] = &types.NameData{
+		Country: map[string]float32{"GB": 0.3},
+		Rank:    map[string]int32{"GB": 150},
+	}
+	detector := New(dataset)
+
+	// Two first names joined by a hyphen
+	result := detector.DetectPIIWithThreshold([]string{"Anne-Marie", "Smith"}, 0.5)
+	if !result.IsLikelyName || !equalStringSlices(result.Details.FirstNames, []string{"Anne-Marie"}) {
+		t.Errorf("Expected Anne-Marie detected as a first name, got %+v (%.3f)", result.Details, result.Confidence)
+	}
+	if len(result.Details.Matches) != 2 || result.Details.Matches[0].Lookup != "compound" || result.Details.Matches[0].Rank != 12 {
+		t.Errorf("Expected a compound match credited with its rarest part, got %+v", result.Details.Matches)
+	}
+
+	// The compound is credited once, like its rarest part on its own
+	if anne := detector.DetectPIIWithThreshold([]string{"Anne", "Smith"}, 0.5); result.Confidence != anne.Confidence {
+		t.Errorf("Expected Anne-Marie to score like Anne (%.3f), got %.3f", anne.Confidence, result.Confidence)
+	}
+
+	// A compound surname only matches among surnames
+	result = detector.DetectPIIWithThreshold([]string{"John", "Lloyd-Webber"}, 0.5)
+	if !result.IsLikelyName || !equalStringSlices(result.Details.Surnames, []string{"Lloyd-Webber"}) {
+		t.Errorf("Expected Lloyd-Webber detected as a surname, got %+v (%.3f)", result.Details, result.Confidence)
+	}
+	if _, ok := detector.LookupFirstName("Lloyd-Webber"); ok {
+		t.Errorf("Expected Lloyd-Webber not to match as a first name")
+	}
+
+	// Every part must match
+	if _, ok := detector.LookupSurname("Garcia-Xyzzy"); ok {
+		t.Errorf("Expected a compound with an unknown part not to match")
+	}
+}
+
+func TestDetectPII_OriginalIndices(t *testing.T) {
+	dataset := createTestDataset()
+	dataset.LastNames["MCDONALD"] = &types.NameData{
+		Country: map[string]float32{"US": 0.4},
+		Rank:    map[string]int32{"US": 65},
+	}
+	detector := New(dataset)
+
+	tests := []struct {
+		name         string
+		words        []string
+		firstIndices []int
+		lastIndices  []int
+	}{
+		{"No dropped words", []string{"John", "Smith"}, []int{0}, []int{1}},
+		{"Dropped number", []string{"John", "123", "Smith"}, []int{0}, []int{2}},
+		{"Dropped empty strings", []string{"", "Jose", "", "Garcia", "Lopez"}, []int{1}, []int{3, 4}},
+		{"Composed surname", []string{"John", "Mc", "Donald"}, []int{0}, []int{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := detector.DetectPIIWithThreshold(tt.words, 0.5)
+
+			if !equalIntSlices(result.Details.FirstNameIndices, tt.firstIndices) {
+				t.Errorf("Expected first name indices %v, got %v", tt.firstIndices, result.Details.FirstNameIndices)
+			}
+			if !equalIntSlices(result.Details.SurnameIndices, tt.lastIndices) {
+				t.Errorf("Expected surname indices %v, got %v", tt.lastIndices, result.Details.SurnameIndices)
+			}
+		})
+	}
+}
+
+func TestCoverageReport(t *testing.T) {
+	detector := New(createTestDataset())
+
+	report := detector.CoverageReport([]string{"Jose", "García", "Smith", "Informe", "", "  "})
+
+	if report.Tokens != 4 {
+		t.Fatalf("Expected 4 tokens, got %d", report.Tokens)
+	}
+	if report.FirstNames != 0.25 {
+		t.Errorf("Expected first name coverage 0.25, got %v", report.FirstNames)
+	}
+	if report.Surnames != 0.5 {
+		t.Errorf("Expected surname coverage 0.5, got %v", report.Surnames)
+	}
+	if report.Either != 0.75 || report.Neither != 0.25 {
+		t.Errorf("Expected either=0.75 neither=0.25, got either=%v neither=%v", report.Either, report.Neither)
+	}
+
+	if empty := detector.CoverageReport(nil); empty.Tokens != 0 || empty.Neither != 0 {
+		t.Errorf("Expected empty report for no tokens, got %+v", empty)
+	}
+}
+
+func TestLookupName(t *testing.T) {
+	detector := New(createTestDataset())
+
+	for _, name := range []string{"García", "garcia", " GARCIA "} {
+		data, ok := detector.LookupSurname(name)
+		if !ok || data.Rank["ES"] != 1 || data.Country["MX"] == 0 {
+			t.Errorf("Expected the GARCIA entry for %q, got %+v (%v)", name, data, ok)
+		}
+	}
+	if data, ok := detector.LookupFirstName("María"); !ok || data.Gender["F"] != 0.99 {
+		t.Errorf("Expected the MARIA entry, got %+v (%v)", data, ok)
+	}
+
+	if _, ok := detector.LookupFirstName("Garcia"); ok {
+		t.Errorf("Expected %q not to be found as a first name", "Garcia")
+	}
+	if data, ok := detector.LookupSurname("Informe"); ok || data != nil {
+		t.Errorf("Expected no entry for an unknown name, got %+v", data)
+	}
+
+	dataset := createTestDataset()
+	dataset.LastNames["MARIA"] = &types.NameData{Rank: map[string]int32{"ES": 900}}
+	detector = New(dataset)
+	if first, last := detector.Lookup("María"); first == nil || last == nil || last.Rank["ES"] != 900 {
+		t.Errorf("Expected both MARIA entries, got %+v and %+v", first, last)
+	}
+	if first, last := detector.Lookup("García"); first != nil || last == nil {
+		t.Errorf("Expected only the surname entry, got %+v and %+v", first, last)
+	}
+	if first, last := detector.Lookup("Informe"); first != nil || last != nil {
+		t.Errorf("Expected no entries for an unknown name, got %+v and %+v", first, last)
+	}
+}
+
+func TestDumpName(t *testing.T) {
+	detector := New(createTestDataset())
+
+	data, err := detector.DumpName("García")
+	if err != nil {
+		t.Fatalf("DumpName failed: %v", err)
+	}
+
+	var dump struct {
+		Name      string          `json:"name"`
+		FirstName json.RawMessage `json:"first_name"`
+		Surname   struct {
+			Rank    map[string]int32 `json:"rank"`
+			MinRank int32            `json:"min_rank"`
+		} `json:"surname"`
+	}
+	if err := json.Unmarshal(data, &dump); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", data, err)
+	}
+	if dump.Name != "García" || string(dump.FirstName) != "null" {
+		t.Errorf("Expected García with no first name entry, got %s", data)
+	}
+	if dump.Surname.Rank["ES"] != 1 || dump.Surname.MinRank != 1 {
+		t.Errorf("Expected the GARCIA surname entry, got %s", data)
+	}
+
+	if _, err := detector.DumpName("Informe"); !errors.Is(err, ErrUnknownName) {
+		t.Errorf("Expected ErrUnknownName, got %v", err)
+	}
+}
+
+func TestLookup_PunctuatedVariants(t *testing.T) {
+	dataset := createTestDataset()
+	dataset.FirstNames["ANNE"] = &types.NameData{
+		Country: map[string]float32{"FR": 0.2},
+		Gender:  map[string]float32{"F": 1.0},
+		Rank:    map[string]int32{"FR": 12},
+	}
+	dataset.FirstNames["MARIE"] = &types.NameData{
+		Country: map[string]float32{"FR": 0.4},
+		Gender:  map[string]float32{"F": 1.0},
+		Rank:    map[string]int32{"FR": 2},
+	}
+	dataset.LastNames["DUPONT"] = &types.NameData{
+		Country: map[string]float32{"FR": 0.5},
+		Rank:    map[string]int32{"FR": 15},
+	}
+	// The same surname stored twice, inconsistently
+	dataset.LastNames["O'BRIEN"] = &types.NameData{
+		Country: map[string]float32{"IE": 0.1},
+		Rank:    map[string]int32{"IE": 4800},
+	}
+	dataset.LastNames["OBRIEN"] = &types.NameData{
+		Country: map[string]float32{"IE": 0.5, "US": 0.2},
+		Rank:    map[string]int32{"IE": 7, "US": 120},
+	}
+	dataset.FirstNames["JEANPIERRE"] = &types.NameData{
+		Country: map[string]float32{"FR": 0.3},
+		Gender:  map[string]float32{"M": 1.0},
+		Rank:    map[string]int32{"FR": 150},
+	}
+	detector := New(dataset)
+
+	result := detector.DetectPII([]string{"Anne-Marie", "Dupont"})
+	if !result.IsLikelyName || !equalStringSlices(result.Details.FirstNames, []string{"Anne-Marie"}) {
+		t.Errorf("Expected Anne-Marie Dupont detected, got %+v (%.3f)", result.Details, result.Confidence)
+	}
+
+	tests := []struct {
+		name        string
+		isFirstName bool
+		rank        int32
+		method      string
+	}{
+		{"O'Brien", false, 7, "punctuation_stripped"}, // Better ranked than the exact O'BRIEN entry
+		{"OBrien", false, 7, "exact"},
+		{"Jean-Pierre", true, 150, "punctuation_stripped"},
+		{"Anne-Marie", true, 12, "compound"},
+	}
+	for _, tt := range tests {
+		nameData, method := detector.scorer.lookupWithMethod(tt.name, tt.isFirstName)
+		if nameData == nil || minRankOf(nameData) != tt.rank || method != tt.method {
+			t.Errorf("%s: expected rank %d by %s, got %+v by %q", tt.name, tt.rank, tt.method, nameData, method)
+		}
+	}
+
+	// The whole token wins a tie with its variants
+	dataset.FirstNames["ANNE-MARIE"] = &types.NameData{Rank: map[string]int32{"FR": 12}}
+	if _, method := detector.scorer.lookupWithMethod("Anne-Marie", true); method != "exact" {
+		t.Errorf("Expected the exact entry to win a tie, got %q", method)
+	}
+}
+
+func TestLimitCombinations(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.MaxCombinations = 8
+	config.AllowSurnameFirst = false // Only forward splits, so counts map to positions
+	detector := NewWithDetectorConfig(createTestDataset(), DefaultScoreConfig(), config)
+
+	// A pathological input far beyond the usual word limit
+	words := make([]string, 500)
+	for i := range words {
+		words[i] = "Jose"
+	}
+
+	combinations, truncated := detector.limitCombinations(detector.generateCombinations(words))
+	if !truncated {
+		t.Errorf("Expected the combination search to be truncated")
+	}
+	if len(combinations) != config.MaxCombinations {
+		t.Fatalf("Expected %d combinations, got %d", config.MaxCombinations, len(combinations))
+	}
+
+	// The most balanced splits are kept, in a deterministic order
+	expected := []int{250, 249, 251, 248, 252, 247, 253, 246}
+	for i, combo := range combinations {
+		if len(combo.FirstNames) != expected[i] {
+			t.Errorf("Combination %d: expected %d first names, got %d", i, expected[i], len(combo.FirstNames))
+		}
+	}
+
+	// Short inputs are untouched
+	combinations, truncated = detector.limitCombinations(detector.generateCombinations([]string{"Jose", "Garcia"}))
+	if truncated || len(combinations) != 1 {
+		t.Errorf("Expected a single untruncated combination, got %d (truncated=%v)", len(combinations), truncated)
+	}
+}
+
+func TestDetectPII_SurnameFirst(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.AllowSurnameFirst = true
+	detector := NewWithDetectorConfig(createTestDataset(), DefaultScoreConfig(), config)
+
+	forward := detector.DetectPII([]string{"Jose", "Garcia"})
+	reversed := detector.DetectPII([]string{"Garcia", "Jose"})
+
+	if !reversed.IsLikelyName {
+		t.Errorf("Expected Garcia Jose to be detected, got confidence %.3f", reversed.Confidence)
+	}
+	if forward.Confidence-reversed.Confidence > 0.05 {
+		t.Errorf("Expected comparable confidence, got %.3f for Jose Garcia and %.3f for Garcia Jose",
+			forward.Confidence, reversed.Confidence)
+	}
+	if !equalStringSlices(reversed.Details.FirstNames, []string{"Jose"}) ||
+		!equalStringSlices(reversed.Details.Surnames, []string{"Garcia"}) {
+		t.Errorf("Expected first name Jose and surname Garcia, got %v and %v",
+			reversed.Details.FirstNames, reversed.Details.Surnames)
+	}
+	if !equalIntSlices(reversed.Details.FirstNameIndices, []int{1}) ||
+		!equalIntSlices(reversed.Details.SurnameIndices, []int{0}) {
+		t.Errorf("Expected first name index [1] and surname index [0], got %v and %v",
+			reversed.Details.FirstNameIndices, reversed.Details.SurnameIndices)
+	}
+
+	// Forward order wins whenever it scores at least as well
+	if !equalStringSlices(forward.Details.FirstNames, []string{"Jose"}) {
+		t.Errorf("Expected forward order for Jose Garcia, got first names %v", forward.Details.FirstNames)
+	}
+	if forward.Details.Order != "western" || reversed.Details.Order != "eastern" {
+		t.Errorf("Expected western and eastern order, got %q and %q", forward.Details.Order, reversed.Details.Order)
+	}
+
+	// Surname-first splits are opt-in
+	strict := New(createTestDataset())
+	if result := strict.DetectPII([]string{"Garcia", "Jose"}); result.Confidence >= reversed.Confidence {
+		t.Errorf("Expected lower confidence without surname-first splits, got %.3f", result.Confidence)
+	}
+}
+
+func TestDetectPII_EasternOrder(t *testing.T) {
+	dataset := createTestDataset()
+	dataset.FirstNames["WEI"] = &types.NameData{
+		Country: map[string]float32{"CN": 0.8, "TW": 0.2},
+		Gender:  map[string]float32{"M": 0.8, "F": 0.2},
+		Rank:    map[string]int32{"CN": 1, "TW": 4},
+	}
+	dataset.LastNames["ZHANG"] = &types.NameData{
+		Country: map[string]float32{"CN": 0.9, "TW": 0.1},
+		Rank:    map[string]int32{"CN": 3, "TW": 9},
+	}
+	config := DefaultDetectorConfig()
+	config.AllowSurnameFirst = true
+	detector := NewWithDetectorConfig(dataset, DefaultScoreConfig(), config)
+
+	result := detector.DetectPII([]string{"Zhang", "Wei"})
+	if !result.IsLikelyName {
+		t.Errorf("Expected Zhang Wei to be detected, got confidence %.3f", result.Confidence)
+	}
+	if !equalStringSlices(result.Details.Surnames, []string{"Zhang"}) ||
+		!equalStringSlices(result.Details.FirstNames, []string{"Wei"}) {
+		t.Errorf("Expected surname Zhang and first name Wei, got %v and %v",
+			result.Details.Surnames, result.Details.FirstNames)
+	}
+	if result.Details.Order != "eastern" {
+		t.Errorf("Expected eastern order, got %q", result.Details.Order)
+	}
+}
+
+func TestDetectPII_AmbiguousTokens(t *testing.T) {
+	dataset := createTestDataset()
+	dataset.LastNames["JOSE"] = &types.NameData{
+		Country: map[string]float32{"ES": 0.1},
+		Rank:    map[string]int32{"ES": 900},
+	}
+
+	config := DefaultDetectorConfig()
+	config.ReportAmbiguousTokens = true
+	detector := NewWithDetectorConfig(dataset, DefaultScoreConfig(), config)
+
+	result := detector.DetectPII([]string{"Jose", "Garcia"})
+	if !equalStringSlices(result.Details.AmbiguousTokens, []string{"Jose"}) {
+		t.Errorf("Expected ambiguous tokens [Jose], got %v", result.Details.AmbiguousTokens)
+	}
+
+	result = detector.DetectPII([]string{"John", "Smith"})
+	if len(result.Details.AmbiguousTokens) != 0 {
+		t.Errorf("Expected no ambiguous tokens, got %v", result.Details.AmbiguousTokens)
+	}
+
+	// Not reported unless enabled
+	result = New(dataset).DetectPII([]string{"Jose", "Garcia"})
+	if len(result.Details.AmbiguousTokens) != 0 {
+		t.Errorf("Expected ambiguous tokens to be opt-in, got %v", result.Details.AmbiguousTokens)
+	}
+}
+
+func TestDetectPII_SideConfidences(t *testing.T) {
+	detector := New(createTestDataset())
+
+	// Known first name, unknown surname
+	result := detector.DetectPII([]string{"Maria", "Xyzzy"})
+	if result.Details.FirstNameConfidence <= 0 {
+		t.Errorf("Expected positive first name confidence, got %.3f", result.Details.FirstNameConfidence)
+	}
+	if result.Details.SurnameConfidence != 0 {
+		t.Errorf("Expected zero surname confidence, got %.3f", result.Details.SurnameConfidence)
+	}
+
+	// Unknown first name, known surname
+	result = detector.DetectPII([]string{"Xyzzy", "Garcia"})
+	if result.Details.FirstNameConfidence != 0 {
+		t.Errorf("Expected zero first name confidence, got %.3f", result.Details.FirstNameConfidence)
+	}
+	if result.Details.SurnameConfidence <= 0 {
+		t.Errorf("Expected positive surname confidence, got %.3f", result.Details.SurnameConfidence)
+	}
+
+	// Both sides stay within [0, 1]
+	result = detector.DetectPII([]string{"Jose", "Manuel", "Garcia", "Lopez"})
+	for name, confidence := range map[string]float64{
+		"first name": result.Details.FirstNameConfidence,
+		"surname":    result.Details.SurnameConfidence,
+	} {
+		if confidence <= 0 || confidence > 1 {
+			t.Errorf("Expected %s confidence in (0, 1], got %.3f", name, confidence)
+		}
+	}
+}
+
+func TestDetectPII_MatchedNames(t *testing.T) {
+	detector := New(createTestDataset())
+
+	result := detector.DetectPIIWithThreshold([]string{"José", "Xyzzy"}, 0.1)
+	if !equalStringSlices(result.Details.MatchedFirstNames, []string{"José"}) {
+		t.Errorf("Expected matched first names [José], got %v", result.Details.MatchedFirstNames)
+	}
+	if len(result.Details.MatchedSurnames) != 0 {
+		t.Errorf("Expected no matched surnames, got %v", result.Details.MatchedSurnames)
+	}
+
+	result = detector.DetectPII([]string{"Jose", "Manuel", "Garcia", "Lopez"})
+	if !equalStringSlices(result.Details.MatchedFirstNames, result.Details.FirstNames) ||
+		!equalStringSlices(result.Details.MatchedSurnames, result.Details.Surnames) {
+		t.Errorf("Expected every component to match, got %v and %v",
+			result.Details.MatchedFirstNames, result.Details.MatchedSurnames)
+	}
+}
+
+func TestDetectPII_MatchDetails(t *testing.T) {
+	dataset := createTestDataset()
+	dataset.LastNames["OBRIEN"] = &types.NameData{
+		Country: map[string]float32{"IE": 0.5},
+		Rank:    map[string]int32{"IE": 7},
+	}
+	detector := New(dataset)
+
+	result := detector.DetectPIIWithThreshold([]string{"José", "O'Brien", "Xyzzy"}, 0.1)
+
+	expected := []types.NameMatchDetail{
+		{Token: "José", Role: "first_name", Rank: 1, Lookup: "normalized"},
+		{Token: "O'Brien", Role: "surname", Rank: 7, Lookup: "punctuation_stripped"},
+	}
+	if len(result.Details.Matches) != len(expected) {
+		t.Fatalf("Expected %d matches, got %+v", len(expected), result.Details.Matches)
+	}
+	for i, want := range expected {
+		got := result.Details.Matches[i]
+		if got.Token != want.Token || got.Role != want.Role || got.Rank != want.Rank || got.Lookup != want.Lookup {
+			t.Errorf("Match %d: expected %+v, got %+v", i, want, got)
+		}
+		if got.Popularity <= 0 {
+			t.Errorf("Match %d: expected a positive popularity contribution, got %.3f", i, got.Popularity)
+		}
+	}
+
+	result = detector.DetectPII([]string{"John", "Smith"})
+	for _, match := range result.Details.Matches {
+		if match.Lookup != "exact" {
+			t.Errorf("Expected exact lookup for %q, got %q", match.Token, match.Lookup)
+		}
+	}
+}
+
+func TestDetectPII_GenericSurnameRank(t *testing.T) {
+	dataset := createTestDataset()
+	dataset.LastNames["ZAMBRANO"] = &types.NameData{
+		Country: map[string]float32{"EC": 0.4},
+		Rank:    map[string]int32{"EC": 2500},
+	}
+
+	config := DefaultDetectorConfig()
+	config.GenericSurnameRank = 100
+	detector := NewWithDetectorConfig(dataset, DefaultScoreConfig(), config)
+	threshold := 0.1
+
+	if !New(dataset).DetectPIIWithThreshold([]string{"Xyzzy", "Smith"}, threshold).IsLikelyName {
+		t.Fatal("Expected a lone common surname to pass without the rule")
+	}
+
+	result := detector.DetectPIIWithThreshold([]string{"Xyzzy", "Smith"}, threshold)
+	if result.IsLikelyName || result.Decision.Passed {
+		t.Errorf("Expected a lone common surname to be rejected, got confidence %.3f", result.Confidence)
+	}
+	if !strings.Contains(result.Decision.Reason, "common surname") {
+		t.Errorf("Expected the reason to name the rule, got %q", result.Decision.Reason)
+	}
+
+	for _, words := range [][]string{
+		{"John", "Smith"},     // corroborated by a first name
+		{"Xyzzy", "Zambrano"}, // distinctive surname
+	} {
+		if result := detector.DetectPIIWithThreshold(words, threshold); !result.IsLikelyName {
+			t.Errorf("Expected %v to pass, got reason %q", words, result.Decision.Reason)
+		}
+	}
+}
+
+func TestSetDataset(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.CacheSize = 16
+	detector := NewWithDetectorConfig(createTestDataset(), DefaultScoreConfig(), config)
+	words := []string{"Zorvath", "Garcia"}
+
+	before := detector.DetectPII(words)
+
+	updated := createTestDataset()
+	updated.FirstNames["ZORVATH"] = &types.NameData{
+		Country: map[string]float32{"ES": 0.5},
+		Rank:    map[string]int32{"ES": 2},
+	}
+
+	// Detections keep running while the dataset is swapped
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				detector.DetectPII([]string{"Jose", "Garcia"})
+			}
+		}()
+	}
+	detector.SetDataset(updated)
+	wg.Wait()
+
+	after := detector.DetectPII(words)
+	if after.Confidence <= before.Confidence {
+		t.Errorf("Expected the new dataset to raise confidence above %.3f, got %.3f", before.Confidence, after.Confidence)
+	}
+}
+
+// Names merged into a fresh copy of the dataset are seen once it is swapped in
+func TestSetDataset_MergedNames(t *testing.T) {
+	detector := New(createTestDataset())
+	words := []string{"Zorvath", "Garcia"}
+	if detector.DetectPII(words).IsLikelyName {
+		t.Fatalf("Expected %v not to be a name before the merge", words)
+	}
+
+	l := loader.New()
+	l.MergeDataset(createTestDataset())
+	l.MergeDataset(&types.NameDataset{FirstNames: map[string]*types.NameData{
+		"Zorvath": {
+			Country: map[string]float32{"ES": 1},
+			Gender:  map[string]float32{"M": 1},
+			Rank:    map[string]int32{"ES": 2},
+		},
+	}})
+	detector.SetDataset(l.GetDataset())
+
+	if result := detector.DetectPII(words); !result.IsLikelyName {
+		t.Errorf("Expected %v to be a name after the merge, got %.3f (%s)", words, result.Confidence, result.Decision.Reason)
+	}
+	if result := detector.DetectPII([]string{"Jose", "Garcia"}); !result.IsLikelyName {
+		t.Errorf("Expected the original names to survive the merge")
+	}
+}
+
+// Resetting the loader leaves detectors on the old dataset intact
+func TestLoaderReset_KeepsDetectorDataset(t *testing.T) {
+	l := loader.New()
+	l.MergeDataset(createTestDataset())
+	detector := New(l.GetDataset())
+	words := []string{"Jose", "Garcia"}
+	before := detector.DetectPII(words)
+
+	l.Reset()
+	if l.IsLoaded() || len(l.GetDataset().FirstNames) != 0 {
+		t.Fatalf("Expected Reset to leave the loader empty")
+	}
+	l.MergeDataset(&types.NameDataset{FirstNames: map[string]*types.NameData{
+		"Jose": {Rank: map[string]int32{"ES": 50000}},
+	}})
+
+	after := detector.DetectPII(words)
+	if !after.IsLikelyName || after.Confidence != before.Confidence {
+		t.Errorf("Expected confidence %.3f after Reset, got %.3f", before.Confidence, after.Confidence)
+	}
+}
+
+func TestDetectPIIE(t *testing.T) {
+	detector := New(createTestDataset())
+
+	tests := []struct {
+		name  string
+		words []string
+		err   error
+	}{
+		{"name", []string{"Jose", "Garcia"}, nil},
+		{"analyzed non-name", []string{"Random", "Words"}, nil},
+		{"too few words", []string{"Jose"}, ErrInvalidWordCount},
+		{"too many words", []string{"Jose", "Maria", "Ana", "Garcia", "Lopez", "Smith", "Rodriguez"}, ErrInvalidWordCount},
+		{"only punctuation left", []string{"Jose", "&"}, ErrInsufficientWords},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := detector.DetectPIIE(tt.words, 0.7)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("DetectPIIE(%v) error = %v, want %v", tt.words, err, tt.err)
+			}
+			if result.Analyzed != (tt.err == nil) {
+				t.Errorf("Expected Analyzed=%v, got %v", tt.err == nil, result.Analyzed)
+			}
+		})
+	}
+}
+
+func TestDetectorConfig_WordLimits(t *testing.T) {
+	long := []string{"Maria", "Jose", "Manuel", "Garcia", "Lopez", "Robles", "Hermoso"}
+
+	// Defaults reject single words and more than six words
+	detector := New(createTestDataset())
+	for _, words := range [][]string{{"Jose"}, long} {
+		if result := detector.DetectPII(words); result.Details.Pattern != "invalid_length" {
+			t.Errorf("Expected invalid_length for %v by default, got %q", words, result.Details.Pattern)
+		}
+	}
+
+	config := DefaultDetectorConfig()
+	config.MinWords = 1
+	config.MaxWords = 8
+	detector = NewWithDetectorConfig(createTestDataset(), DefaultScoreConfig(), config)
+
+	result := detector.DetectPII([]string{"Jose"})
+	if !result.Analyzed || !equalStringSlices(result.Details.FirstNames, []string{"Jose"}) {
+		t.Errorf("Expected a mononym to be scored as a first name, got %v (analyzed %v)", result.Details.FirstNames, result.Analyzed)
+	}
+	if result.Confidence <= 0 {
+		t.Errorf("Expected a popular first name to score as a mononym, got %.3f", result.Confidence)
+	}
+
+	result = detector.DetectPII([]string{"Smith"})
+	if !equalStringSlices(result.Details.Surnames, []string{"Smith"}) {
+		t.Errorf("Expected a lone surname to be scored as a surname, got %v/%v", result.Details.FirstNames, result.Details.Surnames)
+	}
+
+	if result := detector.DetectPII(long); !result.Analyzed || !result.IsLikelyName {
+		t.Errorf("Expected a 7-word name to be analyzed with MaxWords 8, got %q at %.3f", result.Details.Pattern, result.Confidence)
+	}
+
+	_, err := detector.DetectPIIE(append(long, "Lopez", "Smith"), 0.7)
+	if !errors.Is(err, ErrInvalidWordCount) || !strings.Contains(err.Error(), "want 1 to 8") {
+		t.Errorf("Expected ErrInvalidWordCount naming the configured limits, got %v", err)
+	}
+
+	// A mononym needs a single word to survive cleaning
+	result, err = detector.DetectPIIE([]string{"&", "!"}, 0.7)
+	if !errors.Is(err, ErrInsufficientWords) || !strings.Contains(err.Error(), "want at least 1") {
+		t.Errorf("Expected ErrInsufficientWords naming the configured minimum, got %v", err)
+	}
+	if !strings.Contains(result.Decision.Reason, "at least 1 word besides") {
+		t.Errorf("Expected the reason to name the configured minimum, got %q", result.Decision.Reason)
+	}
+}
+
+func TestDetectorConfig_DetectMononyms(t *testing.T) {
+	dataset := createTestDataset()
+	dataset.LastNames["HERMOSO"].Rank = map[string]int32{"ES": 3682}
+
+	if result := New(dataset).DetectPII([]string{"Garcia"}); result.Analyzed {
+		t.Errorf("Expected single words to be rejected without DetectMononyms, got %q", result.Details.Pattern)
+	}
+
+	config := DefaultDetectorConfig()
+	config.DetectMononyms = true
+	detector := NewWithDetectorConfig(dataset, DefaultScoreConfig(), config)
+
+	tests := []struct {
+		word      string
+		isSurname bool
+		isName    bool
+	}{
+		{"Jose", false, true},    // Top-ranked first name
+		{"García", true, true},   // Top-ranked surname, accent-insensitive
+		{"Hermoso", true, false}, // Rare surname scores low
+	}
+
+	for _, tt := range tests {
+		result := detector.DetectPII([]string{tt.word})
+		if result.Details.Pattern != "1_mononym" {
+			t.Errorf("DetectPII(%q) pattern = %q, want 1_mononym", tt.word, result.Details.Pattern)
+		}
+		if got := len(result.Details.Surnames) == 1; got != tt.isSurname {
+			t.Errorf("DetectPII(%q) matched as surname = %v, want %v", tt.word, got, tt.isSurname)
+		}
+		if result.IsLikelyName != tt.isName {
+			t.Errorf("DetectPII(%q) = %v at %.3f, want %v", tt.word, result.IsLikelyName, result.Confidence, tt.isName)
+		}
+	}
+
+	// Longer input is scored as before
+	if result := detector.DetectPII([]string{"Jose", "Garcia"}); result.Details.Pattern != "1_first_1_last" {
+		t.Errorf("Expected two-word input to keep its pattern, got %q", result.Details.Pattern)
+	}
+}
+
+func TestFindBestCombination_TieBreaking(t *testing.T) {
+	// Both tokens are top-ranked surnames only, so both orders score the same
+	// and the forward split is kept
+	detector := New(createTestDataset())
+	for i := 0; i < 10; i++ {
+		result := detector.DetectPII([]string{"Garcia", "Lopez"})
+		if !equalStringSlices(result.Details.FirstNames, []string{"Garcia"}) || !equalStringSlices(result.Details.Surnames, []string{"Lopez"}) {
+			t.Fatalf("Expected the forward split Garcia/Lopez on a tie, got %v/%v", result.Details.FirstNames, result.Details.Surnames)
+		}
+	}
+
+	// With identical surname data and first names in the same popularity
+	// tier, scores still tie and the better-ranked first name wins
+	dataset := createTestDataset()
+	dataset.LastNames["LOPEZ"] = dataset.LastNames["GARCIA"]
+	dataset.FirstNames["GARCIA"] = &types.NameData{
+		Country: map[string]float32{"ES": 0.1},
+		Gender:  map[string]float32{"M": 1.0},
+		Rank:    map[string]int32{"ES": 45},
+	}
+	dataset.FirstNames["LOPEZ"] = &types.NameData{
+		Country: map[string]float32{"ES": 0.1},
+		Gender:  map[string]float32{"M": 1.0},
+		Rank:    map[string]int32{"ES": 20},
+	}
+	config := DefaultDetectorConfig()
+	config.AllowSurnameFirst = true
+	detector = NewWithDetectorConfig(dataset, DefaultScoreConfig(), config)
+	garcia := detector.scorer.rawScore(types.NameCombination{FirstNames: []string{"Garcia"}, Surnames: []string{"Lopez"}})
+	lopez := detector.scorer.rawScore(types.NameCombination{FirstNames: []string{"Lopez"}, Surnames: []string{"Garcia"}, Reversed: true})
+	if garcia != lopez {
+		t.Fatalf("Expected both splits to tie, got %.4f and %.4f", garcia, lopez)
+	}
+
+	result := detector.DetectPII([]string{"Garcia", "Lopez"})
+	if !equalStringSlices(result.Details.FirstNames, []string{"Lopez"}) || !equalStringSlices(result.Details.Surnames, []string{"Garcia"}) {
+		t.Errorf("Expected the better-ranked first name Lopez to win the tie, got %v/%v", result.Details.FirstNames, result.Details.Surnames)
+	}
+}
+
+func TestCountryDistribution(t *testing.T) {
+	detector := New(createTestDataset())
+
+	result := detector.DetectPII([]string{"Jose", "Garcia"})
+	distribution := result.Details.CountryDistribution
+	if len(distribution) != 3 {
+		t.Fatalf("Expected ES, MX and US in the distribution, got %v", distribution)
+	}
+
+	var total, best float64
+	var top string
+	for country, probability := range distribution {
+		total += probability
+		if probability > best {
+			best, top = probability, country
+		}
+	}
+	if math.Abs(total-1.0) > 1e-9 {
+		t.Errorf("Expected the distribution to sum to 1.0, got %.6f", total)
+	}
+	if top != result.Details.TopCountry {
+		t.Errorf("Expected TopCountry %q to be the largest entry, got %q", result.Details.TopCountry, top)
+	}
+
+	// MX share of the summed country probabilities of Jose and Garcia
+	if expected := (0.203 + 0.234) / (0.159 + 0.203 + 0.098 + 0.11 + 0.234 + 0.156); math.Abs(distribution["MX"]-expected) > 1e-6 {
+		t.Errorf("Expected MX share %.4f, got %.4f", expected, distribution["MX"])
+	}
+
+	if result := detector.DetectPII([]string{"Random", "Words"}); result.Details.CountryDistribution != nil {
+		t.Errorf("Expected no distribution without matches, got %v", result.Details.CountryDistribution)
+	}
+}
+
+func TestGetTopCountries(t *testing.T) {
+	dataset := createTestDataset()
+	dataset.LastNames["PEREZ"] = &types.NameData{
+		Country: map[string]float32{"AR": 0.3, "CO": 0.3, "ES": 0.2, "MX": 0.2},
+		Rank:    map[string]int32{"AR": 5, "CO": 5, "ES": 9, "MX": 9},
+	}
+	scorer := NewScorer(dataset, DefaultScoreConfig())
+	combo := types.NameCombination{FirstNames: []string{"Unknownname"}, Surnames: []string{"Perez"}}
+
+	expected := []types.CountryScore{{Country: "AR", Score: 0.3}, {Country: "CO", Score: 0.3}, {Country: "ES", Score: 0.2}}
+	countries := scorer.GetTopCountries(combo, 3)
+	if len(countries) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, countries)
+	}
+	for i, want := range expected {
+		if countries[i].Country != want.Country || math.Abs(countries[i].Score-want.Score) > 1e-6 {
+			t.Errorf("Position %d: expected %v, got %v", i, want, countries[i])
+		}
+	}
+
+	if all := scorer.GetTopCountries(combo, 0); len(all) != 4 || all[3].Country != "MX" {
+		t.Errorf("Expected every country with n <= 0, got %v", all)
+	}
+	if none := scorer.GetTopCountries(types.NameCombination{FirstNames: []string{"Xyzzy"}, Surnames: []string{"Plugh"}}, 3); none != nil {
+		t.Errorf("Expected nil without country data, got %v", none)
+	}
+
+	result := New(dataset).DetectPII([]string{"Jose", "Garcia"})
+	if len(result.Details.TopCountries) != 3 || result.Details.TopCountries[0].Country != result.Details.TopCountry {
+		t.Errorf("Expected TopCountries led by %q, got %v", result.Details.TopCountry, result.Details.TopCountries)
+	}
+}
+
+func TestGetTopCountry_Ties(t *testing.T) {
+	dataset := createTestDataset()
+	dataset.LastNames["PEREZ"] = &types.NameData{
+		Country: map[string]float32{"MX": 0.25, "CO": 0.25, "AR": 0.25, "PE": 0.25},
+		Rank:    map[string]int32{"AR": 5, "CO": 5, "MX": 5, "PE": 5},
+	}
+	scorer := NewScorer(dataset, DefaultScoreConfig())
+	detector := New(dataset)
+	combo := types.NameCombination{FirstNames: []string{"Unknownname"}, Surnames: []string{"Perez"}}
+
+	// Map iteration order varies between runs, so repeat to catch instability
+	for i := 0; i < 200; i++ {
+		if country := scorer.GetTopCountry(combo); country != "AR" {
+			t.Fatalf("Run %d: expected the tie to go to AR, got %q", i, country)
+		}
+		if result := detector.DetectPIIWithThreshold([]string{"Xyzzy", "Perez"}, 0.1); result.Details.TopCountry != "AR" {
+			t.Fatalf("Run %d: expected TopCountry AR, got %q", i, result.Details.TopCountry)
+		}
+	}
+}
+
+func TestPrecomputedMinRank(t *testing.T) {
+	if rank := types.BestRank(map[string]int32{"ES": 141, "MX": 67, "US": 0}); rank != 67 {
+		t.Errorf("Expected best rank 67, got %d", rank)
+	}
+	if rank := types.BestRank(nil); rank != 0 {
+		t.Errorf("Expected 0 without ranks, got %d", rank)
+	}
+
+	words := [][]string{{"Jose", "Manuel", "Robles", "Hermoso"}, {"Maria", "Garcia"}, {"John", "Smith"}}
+	scanned := New(createTestDataset())
+	precomputed := New(withMinRanks(createTestDataset()))
+	for _, input := range words {
+		if a, b := scanned.DetectPII(input), precomputed.DetectPII(input); a.Confidence != b.Confidence {
+			t.Errorf("%v: expected precomputed MinRank to score like scanning Rank, got %.4f and %.4f", input, b.Confidence, a.Confidence)
+		}
+	}
+}
+
+// withMinRanks fills in the MinRank of every entry, as the loader does
+func withMinRanks(dataset *types.NameDataset) *types.NameDataset {
+	for _, nameMap := range []map[string]*types.NameData{dataset.FirstNames, dataset.LastNames} {
+		for _, nameData := range nameMap {
+			nameData.MinRank = types.BestRank(nameData.Rank)
+		}
+	}
+	return dataset
+}
+
+func TestDetectPIIConcurrent(t *testing.T) {
+	inputs := [][]string{
+		{"Jose", "Garcia"},
+		{"Maria", "Rodriguez", "Lopez"},
+		{"Garcia", "Jose"},
+		{"Random", "Words"},
+		{"John", "Smith"},
+	}
+
+	cached := DefaultDetectorConfig()
+	cached.CacheSize = 3 // Smaller than the input set so entries are evicted
+	detectors := map[string]*Detector{
+		"uncached": New(createTestDataset()),
+		"cached":   NewWithDetectorConfig(createTestDataset(), DefaultScoreConfig(), cached),
+	}
+
+	for name, detector := range detectors {
+		t.Run(name, func(t *testing.T) {
+			expected := make([]types.PIIResult, len(inputs))
+			for i, words := range inputs {
+				expected[i] = New(createTestDataset()).DetectPII(words)
+			}
+
+			var wg sync.WaitGroup
+			for g := 0; g < 200; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for j := 0; j < 20; j++ {
+						i := (g + j) % len(inputs)
+						result := detector.DetectPII(inputs[i])
+						if result.IsLikelyName != expected[i].IsLikelyName || result.Confidence != expected[i].Confidence {
+							t.Errorf("DetectPII(%v) = %v/%.3f concurrently, want %v/%.3f",
+								inputs[i], result.IsLikelyName, result.Confidence, expected[i].IsLikelyName, expected[i].Confidence)
+							return
+						}
+					}
+				}(g)
+			}
+			wg.Wait()
+		})
+	}
+}
+
 // Helper function to compare string slices
 func equalStringSlices(a, b []string) bool {
 	if len(a) != len(b) {
@@ -320,6 +1323,19 @@ func equalStringSlices(a, b []string) bool {
 	return true
 }
 
+// Helper function to compare int slices
+func equalIntSlices(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // Benchmark tests
 func BenchmarkDetectPII_Spanish(b *testing.B) {
 	dataset := createTestDataset()
@@ -374,4 +1390,106 @@ func BenchmarkDetectPII_MixedAccents(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		detector.DetectPII(words)
 	}
-}
\ No newline at end of file
+}
+
+// Benchmark a multi-word name with ranks scanned on every lookup and with
+// MinRank precomputed as the loader does
+func BenchmarkDetectPII_MinRank(b *testing.B) {
+	words := []string{"Jose", "Manuel", "Robles", "Hermoso"}
+
+	for _, bench := range []struct {
+		name    string
+		dataset *types.NameDataset
+	}{
+		{"scan", createTestDataset()},
+		{"precomputed", withMinRanks(createTestDataset())},
+	} {
+		detector := New(bench.dataset)
+		b.Run(bench.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				detector.DetectPII(words)
+			}
+		})
+	}
+}
+
+var (
+	embeddedOnce    sync.Once
+	embeddedData    *types.NameDataset
+	embeddedLoadErr error
+)
+
+// embeddedDataset loads the embedded dataset once for the tests that check
+// scores on real data, skipping them in short mode
+func embeddedDataset(t *testing.T) *types.NameDataset {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("loads the embedded dataset")
+	}
+
+	embeddedOnce.Do(func() {
+		l, err := loader.NewWithEmbeddedData()
+		if err != nil {
+			embeddedLoadErr = err
+			return
+		}
+		embeddedData = l.GetDataset()
+	})
+	if embeddedLoadErr != nil {
+		t.Fatalf("Failed to load the embedded dataset: %v", embeddedLoadErr)
+	}
+	return embeddedData
+}
+
+// Test that generalizing the strong-pair boost to multi-token names left the
+// scores of two-token names on the embedded dataset as they were
+func TestDetectPII_TwoTokenScoresUnchanged(t *testing.T) {
+	detector := New(embeddedDataset(t))
+
+	tests := []struct {
+		input      string
+		confidence float64
+		isName     bool
+	}{
+		{"John Smith", 0.71655, true},
+		{"Mary Smith", 0.66435, false},
+		{"José García", 0.64765, false},
+		{"Garcia Lopez", 0.68020, false},
+		{"Andrea Rossi", 0.69645, false},
+		{"Maria Lopez", 0.44485, false},
+		{"Emma Johnson", 0.91203, true},
+		{"Mohammed Ali", 0.45010, false},
+	}
+
+	for _, tt := range tests {
+		result := detector.DetectPII(strings.Fields(tt.input))
+		if math.Abs(result.Confidence-tt.confidence) > 1e-4 || result.IsLikelyName != tt.isName {
+			t.Errorf("%s: expected %.4f (%v), got %.4f (%v)",
+				tt.input, tt.confidence, tt.isName, result.Confidence, result.IsLikelyName)
+		}
+	}
+}
+
+// Test that Scandinavian "af" and "av" names on the embedded dataset score as
+// they did before language-scoped prepositions, which leave them out of the
+// defaults
+func TestDetectPII_ScandinavianParticlesUnchanged(t *testing.T) {
+	detector := New(embeddedDataset(t))
+
+	tests := []struct {
+		input      string
+		confidence float64
+		firstNames []string
+	}{
+		{"Lars af Klint", 0.68863, []string{"Lars", "af"}},
+		{"Anna av Klint", 0.58808, []string{"Anna"}},
+	}
+
+	for _, tt := range tests {
+		result := detector.DetectPII(strings.Fields(tt.input))
+		if math.Abs(result.Confidence-tt.confidence) > 1e-4 || !equalStringSlices(result.Details.FirstNames, tt.firstNames) {
+			t.Errorf("%s: expected %.4f with first names %v, got %.4f with %v",
+				tt.input, tt.confidence, tt.firstNames, result.Confidence, result.Details.FirstNames)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package detector
 
 import (
+	"math"
 	"testing"
 
 	"github.com/montevive/go-name-detector/pkg/types"
@@ -129,10 +130,570 @@ func TestCalculatePopularityScore(t *testing.T) {
 			Rank: map[string]int32{"TEST": tt.rank},
 		}
 
-		score := scorer.calculatePopularityScore(nameData)
+		score := scorer.calculatePopularityScore(nameData, true)
 		if score != tt.expected {
 			t.Errorf("Rank %d (%s tier): expected %.2f, got %.2f", 
 				tt.rank, tt.tier, tt.expected, score)
 		}
 	}
-}
\ No newline at end of file
+}
+
+// Test that percentile ranks discount names ranked well only in short lists
+func TestCalculatePopularityScore_PercentileRanks(t *testing.T) {
+	dataset := &types.NameDataset{
+		FirstNames: map[string]*types.NameData{
+			"BIGTOP":   {Rank: map[string]int32{"US": 10}},
+			"SMALLTOP": {Rank: map[string]int32{"SV": 10}},
+			"BOTH":     {Rank: map[string]int32{"US": 40, "SV": 5}},
+			"USLAST":   {Rank: map[string]int32{"US": 20000}}, // Sets the US list size
+			"SVLAST":   {Rank: map[string]int32{"SV": 500}},   // Sets the SV list size
+		},
+		LastNames: map[string]*types.NameData{},
+	}
+
+	config := DefaultScoreConfig()
+	absolute := NewScorer(dataset, config)
+	config.PercentileRanks = true
+	percentile := NewScorer(dataset, config)
+
+	tests := []struct {
+		name               string
+		absolute, expected float64
+	}{
+		{"BIGTOP", 1.0, 1.0},   // The longest list keeps its ranks
+		{"SMALLTOP", 1.0, 0.2}, // 10 of 500 rescales to 400 of 20,000
+		{"BOTH", 1.0, 0.8},     // Its US rank is now the better one
+		{"SVLAST", 0.2, 0.02},  // Last of a short list is as rare as last of a long one
+	}
+
+	for _, tt := range tests {
+		nameData := dataset.FirstNames[tt.name]
+		if score := absolute.calculatePopularityScore(nameData, true); score != tt.absolute {
+			t.Errorf("%s: expected %.2f by absolute rank, got %.2f", tt.name, tt.absolute, score)
+		}
+		if score := percentile.calculatePopularityScore(nameData, true); score != tt.expected {
+			t.Errorf("%s: expected %.2f by percentile rank, got %.2f", tt.name, tt.expected, score)
+		}
+	}
+
+	// Sizes filled in by the loader are used as they are
+	dataset.FirstNameListSizes = map[string]int32{"US": 20000, "SV": 20000}
+	percentile.SetDataset(&types.NameDataset{
+		FirstNames:         dataset.FirstNames,
+		LastNames:          dataset.LastNames,
+		FirstNameListSizes: dataset.FirstNameListSizes,
+	})
+	if score := percentile.calculatePopularityScore(dataset.FirstNames["SMALLTOP"], true); score != 1.0 {
+		t.Errorf("Expected the given list sizes to keep SMALLTOP in the top tier, got %.2f", score)
+	}
+}
+
+// Test that the averaging mode controls whether unmatched tokens dilute the score
+func TestScoreCombination_AveragingMode(t *testing.T) {
+	dataset := createTestDataset()
+
+	matched := types.NameCombination{
+		FirstNames: []string{"Jose", "Manuel"},
+		Surnames:   []string{"Garcia", "Lopez"},
+	}
+	diluted := types.NameCombination{
+		FirstNames: []string{"Jose", "Manuel", "Xyzzy"},
+		Surnames:   []string{"Garcia", "Lopez"},
+	}
+
+	allTokens := NewScorer(dataset, DefaultScoreConfig())
+	if allTokens.ScoreCombination(diluted) >= allTokens.ScoreCombination(matched) {
+		t.Errorf("Expected unmatched token to dilute the score with AverageAllTokens")
+	}
+
+	// The top-pair boost needs every token known, so leave it out to
+	// compare the averages alone
+	config := DefaultScoreConfig()
+	config.Averaging = AverageMatchedTokens
+	config.TopPairTiers = nil
+	matchedOnly := NewScorer(dataset, config)
+	if a, b := matchedOnly.ScoreCombination(matched), matchedOnly.ScoreCombination(diluted); a != b {
+		t.Errorf("Expected equal scores with AverageMatchedTokens, got %.3f and %.3f", a, b)
+	}
+
+	unmatched := types.NameCombination{FirstNames: []string{"Xyzzy"}, Surnames: []string{"Qwerty"}}
+	if score := matchedOnly.ScoreCombination(unmatched); score != 0 {
+		t.Errorf("Expected zero score when nothing matches, got %.3f", score)
+	}
+}
+
+// Test that rare real names are distinguished from tokens missing from the dataset
+func TestDetectPII_RareVersusUnknown(t *testing.T) {
+	detector := New(createTestDataset())
+
+	rare := detector.DetectPII([]string{"Jose", "Hermoso"})
+	if !rare.Details.HasRareMatches || rare.Details.HasUnknownTokens {
+		t.Errorf("Jose Hermoso: expected rare match and no unknown tokens, got rare=%v unknown=%v",
+			rare.Details.RareTokens, rare.Details.UnknownTokens)
+	}
+	if !equalStringSlices(rare.Details.RareTokens, []string{"Hermoso"}) {
+		t.Errorf("Jose Hermoso: expected rare tokens [Hermoso], got %v", rare.Details.RareTokens)
+	}
+
+	unknown := detector.DetectPII([]string{"Jose", "Qwerty"})
+	if unknown.Details.HasRareMatches || !unknown.Details.HasUnknownTokens {
+		t.Errorf("Jose Qwerty: expected unknown token and no rare match, got rare=%v unknown=%v",
+			unknown.Details.RareTokens, unknown.Details.UnknownTokens)
+	}
+	if !equalStringSlices(unknown.Details.UnknownTokens, []string{"Qwerty"}) {
+		t.Errorf("Jose Qwerty: expected unknown tokens [Qwerty], got %v", unknown.Details.UnknownTokens)
+	}
+
+	common := detector.DetectPII([]string{"Jose", "Garcia"})
+	if common.Details.HasRareMatches || common.Details.HasUnknownTokens {
+		t.Errorf("Jose Garcia: expected neither flag, got rare=%v unknown=%v",
+			common.Details.RareTokens, common.Details.UnknownTokens)
+	}
+}
+
+// Test that tokens used in their best-ranked role earn the role-fit bonus
+func TestScoreCombination_RoleFit(t *testing.T) {
+	dataset := createTestDataset()
+	// Lopez is also a (rare) first name; Manuel is also a (rare) surname
+	dataset.FirstNames["LOPEZ"] = &types.NameData{
+		Country: map[string]float32{"ES": 0.01},
+		Gender:  map[string]float32{"M": 1.0},
+		Rank:    map[string]int32{"ES": 5000},
+	}
+	dataset.LastNames["MANUEL"] = &types.NameData{
+		Country: map[string]float32{"ES": 0.01},
+		Rank:    map[string]int32{"ES": 4000},
+	}
+	scorer := NewScorer(dataset, DefaultScoreConfig())
+
+	fitting := types.NameCombination{FirstNames: []string{"Manuel"}, Surnames: []string{"Lopez"}}
+	if fit := scorer.RoleFit(fitting); fit != 1.0 {
+		t.Errorf("Expected role fit 1.0 for Manuel Lopez, got %.2f", fit)
+	}
+
+	swapped := types.NameCombination{FirstNames: []string{"Lopez"}, Surnames: []string{"Manuel"}}
+	if fit := scorer.RoleFit(swapped); fit != 0.0 {
+		t.Errorf("Expected role fit 0.0 for Lopez Manuel, got %.2f", fit)
+	}
+
+	// The bonus is off by default and its weight is configurable
+	config := DefaultScoreConfig()
+	config.RoleFitBonus = 0.05
+	withBonus := NewScorer(dataset, config).ScoreCombination(fitting)
+	withoutBonus := scorer.ScoreCombination(fitting)
+	if withBonus <= withoutBonus {
+		t.Errorf("Expected role-fit bonus to raise the score, got %.3f vs %.3f", withBonus, withoutBonus)
+	}
+
+	result := New(dataset).DetectPII([]string{"Manuel", "Lopez"})
+	if result.Details.RoleFit != 1.0 {
+		t.Errorf("Expected RoleFit 1.0 in details, got %.2f", result.Details.RoleFit)
+	}
+}
+
+// Test that the strong-pair boost applies regardless of token count
+func TestScoreCombination_TopPairBoost(t *testing.T) {
+	dataset := createTestDataset()
+	detector := New(dataset)
+
+	two := detector.DetectPII([]string{"José", "García"})
+	three := detector.DetectPII([]string{"José", "Manuel", "García"})
+
+	if three.Details.Pattern != "2_first_1_last" {
+		t.Errorf("Expected 2_first_1_last for José Manuel García, got %s", three.Details.Pattern)
+	}
+	if three.Confidence < two.Confidence {
+		t.Errorf("Expected José Manuel García (%.3f) to score at least as high as José García (%.3f)",
+			three.Confidence, two.Confidence)
+	}
+
+	combo := types.NameCombination{
+		FirstNames: []string{"Jose", "Manuel"},
+		Surnames:   []string{"Garcia"},
+	}
+
+	noBoost := DefaultScoreConfig()
+	noBoost.TopPairTiers = nil
+	base := NewScorer(dataset, noBoost).rawScore(combo)
+
+	boosted := NewScorer(dataset, DefaultScoreConfig()).rawScore(combo)
+	if boosted <= base {
+		t.Errorf("Expected the top-100 tier to boost a multi-token name, got %.3f vs %.3f", boosted, base)
+	}
+
+	// The strictest matching tier wins, whatever order tiers are listed in
+	tiered := DefaultScoreConfig()
+	tiered.TopPairTiers = []TopPairTier{
+		{MaxRank: 100, Multiplier: 1.4},
+		{MaxRank: 10, Multiplier: 1.6},
+	}
+	if got := NewScorer(dataset, tiered).rawScore(combo); got <= boosted {
+		t.Errorf("Expected the top-10 tier to apply, got %.3f vs %.3f", got, boosted)
+	}
+
+	// Unknown tokens disable the boost
+	unknown := types.NameCombination{
+		FirstNames: []string{"Jose", "Xyzzy"},
+		Surnames:   []string{"Garcia"},
+	}
+	if _, ok := NewScorer(dataset, DefaultScoreConfig()).topPairMultiplier(unknown); ok {
+		t.Errorf("Expected no boost when a token is missing from its role")
+	}
+}
+
+// Test the optional credit for name-shaped tokens missing from the dataset
+func TestScoreCombination_UnknownTokenWeight(t *testing.T) {
+	dataset := createTestDataset()
+
+	off := NewScorer(dataset, DefaultScoreConfig())
+	config := DefaultScoreConfig()
+	config.UnknownTokenWeight = 0.2
+	on := NewScorer(dataset, config)
+
+	tests := []struct {
+		name     string
+		combo    types.NameCombination
+		credited bool
+	}{
+		{
+			name:     "Capitalized unknown surname",
+			combo:    types.NameCombination{FirstNames: []string{"José"}, Surnames: []string{"Unknownsurname"}},
+			credited: true,
+		},
+		{
+			name:     "Lowercase unknown surname",
+			combo:    types.NameCombination{FirstNames: []string{"José"}, Surnames: []string{"unknownsurname"}},
+			credited: false,
+		},
+		{
+			name:     "All-caps acronym",
+			combo:    types.NameCombination{FirstNames: []string{"José"}, Surnames: []string{"IBM"}},
+			credited: false,
+		},
+		{
+			name:     "Capitalized code with digits",
+			combo:    types.NameCombination{FirstNames: []string{"José"}, Surnames: []string{"Room101"}},
+			credited: false,
+		},
+		{
+			name:     "No known token in the combination",
+			combo:    types.NameCombination{FirstNames: []string{"Apple"}, Surnames: []string{"Unknownsurname"}},
+			credited: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offScore := off.ScoreCombination(tt.combo)
+			onScore := on.ScoreCombination(tt.combo)
+
+			if tt.credited && onScore <= offScore {
+				t.Errorf("Expected credit: on=%.3f should exceed off=%.3f", onScore, offScore)
+			}
+			if !tt.credited && onScore != offScore {
+				t.Errorf("Expected no credit: on=%.3f, off=%.3f", onScore, offScore)
+			}
+		})
+	}
+}
+
+// Test the optional discount for names whose entries have no country data
+func TestScoreCombination_MissingCountryDiscount(t *testing.T) {
+	dataset := createTestDataset()
+	dataset.LastNames["NOWHERE"] = &types.NameData{
+		Country: map[string]float32{},
+		Gender:  map[string]float32{},
+		Rank:    map[string]int32{"ES": 1},
+	}
+
+	off := NewScorer(dataset, DefaultScoreConfig())
+	config := DefaultScoreConfig()
+	config.MissingCountryDiscount = 0.1
+	on := NewScorer(dataset, config)
+
+	gap := types.NameCombination{FirstNames: []string{"José"}, Surnames: []string{"Nowhere"}}
+	if on.ScoreCombination(gap) >= off.ScoreCombination(gap) {
+		t.Errorf("Expected discount for a surname without country data")
+	}
+
+	full := types.NameCombination{FirstNames: []string{"José"}, Surnames: []string{"García"}}
+	if on.ScoreCombination(full) != off.ScoreCombination(full) {
+		t.Errorf("Expected no discount when every name has country data")
+	}
+
+	if on.OriginUnknown(gap) {
+		t.Errorf("Expected origin known when the first name has country data")
+	}
+	if !on.OriginUnknown(types.NameCombination{Surnames: []string{"Nowhere"}}) {
+		t.Errorf("Expected origin unknown when no matched name has country data")
+	}
+	if on.OriginUnknown(types.NameCombination{Surnames: []string{"Unknownsurname"}}) {
+		t.Errorf("Expected origin not flagged when nothing matched")
+	}
+}
+
+// Test the score ceiling for combinations made only of rare names
+func TestScoreCombination_NoiseFloor(t *testing.T) {
+	dataset := createTestDataset()
+	dataset.FirstNames["XIOMARA"] = &types.NameData{
+		Country: map[string]float32{"ES": 0.2, "MX": 0.1},
+		Gender:  map[string]float32{"F": 1.0},
+		Rank:    map[string]int32{"ES": 4210, "MX": 2950},
+	}
+	dataset.LastNames["ZUBIZARRETA"] = &types.NameData{
+		Country: map[string]float32{"ES": 0.3, "MX": 0.05},
+		Gender:  map[string]float32{},
+		Rank:    map[string]int32{"ES": 5120, "MX": 9870},
+	}
+
+	off := DefaultScoreConfig()
+	off.NoiseFloor = NoiseFloor{}
+	uncapped := NewScorer(dataset, off)
+	capped := NewScorer(dataset, DefaultScoreConfig())
+	ceiling := DefaultScoreConfig().NoiseFloor.MaxScore
+
+	// Hermoso ranks 3682; the multiple-names bonus lifts the rare trio above the ceiling
+	rare := types.NameCombination{FirstNames: []string{"Xiomara"}, Surnames: []string{"Hermoso", "Zubizarreta"}}
+	if got := uncapped.ScoreCombination(rare); got <= ceiling {
+		t.Fatalf("Expected the rare combination to score above %.2f without the floor, got %.3f", ceiling, got)
+	}
+	if got := capped.ScoreCombination(rare); got != ceiling {
+		t.Errorf("Expected the rare combination capped at %.2f, got %.3f", ceiling, got)
+	}
+
+	var found bool
+	for _, factor := range capped.Factors(rare) {
+		if factor.Name == "noise_floor" {
+			found = factor.Impact < 0
+		}
+	}
+	if !found {
+		t.Errorf("Expected a negative noise_floor factor")
+	}
+
+	// One common name is enough to lift the cap
+	mixed := types.NameCombination{FirstNames: []string{"Jose"}, Surnames: []string{"Hermoso", "Zubizarreta"}}
+	if capped.ScoreCombination(mixed) != uncapped.ScoreCombination(mixed) {
+		t.Errorf("Expected no cap when a matched name is common")
+	}
+}
+
+// Test the optional logistic calibration of the final score
+func TestCalibration(t *testing.T) {
+	calibration := DefaultCalibration()
+
+	previous := calibration.Apply(0.01)
+	for raw := 0.02; raw <= 1.0; raw += 0.01 {
+		calibrated := calibration.Apply(raw)
+		if calibrated <= previous {
+			t.Errorf("Expected calibration to be strictly increasing, got %.4f at %.2f after %.4f", calibrated, raw, previous)
+		}
+		if calibrated <= 0 || calibrated >= 1 {
+			t.Errorf("Expected a probability strictly between 0 and 1 at %.2f, got %.4f", raw, calibrated)
+		}
+		previous = calibrated
+	}
+
+	if got := calibration.Apply(calibration.Midpoint); math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("Expected the midpoint to map to 0.5, got %.4f", got)
+	}
+	if got := calibration.Apply(0); got != 0 {
+		t.Errorf("Expected a zero score to stay zero, got %.4f", got)
+	}
+	if got := (Calibration{}).Apply(0.42); got != 0.42 {
+		t.Errorf("Expected a zero slope to leave scores unchanged, got %.4f", got)
+	}
+
+	config := DefaultScoreConfig()
+	config.Calibration = calibration
+	calibrated := NewWithConfig(createTestDataset(), config)
+	plain := New(createTestDataset())
+
+	for _, words := range [][]string{{"Jose", "Garcia"}, {"Maria", "Hermoso"}, {"Apple", "Garcia"}} {
+		raw := plain.DetectPIIWithThreshold(words, 0.5).Confidence
+		result := calibrated.DetectPIIWithThreshold(words, 0.5)
+		if want := calibration.Apply(raw); math.Abs(result.Confidence-want) > 1e-9 {
+			t.Errorf("%v: expected calibrated confidence %.4f, got %.4f", words, want, result.Confidence)
+		}
+		if result.Decision.Confidence != result.Confidence {
+			t.Errorf("%v: expected the decision to use the calibrated confidence", words)
+		}
+		if candidates := calibrated.DetectCandidates(words, 1); candidates[0].Score != result.Confidence {
+			t.Errorf("%v: expected the top candidate score %.4f to match the confidence %.4f",
+				words, candidates[0].Score, result.Confidence)
+		}
+	}
+}
+
+func TestScoreConfig_CustomWordLists(t *testing.T) {
+	dataset := createTestDataset()
+	dataset.LastNames["DI"] = &types.NameData{
+		Country: map[string]float32{"IT": 0.1},
+		Rank:    map[string]int32{"IT": 40},
+	}
+	combo := types.NameCombination{FirstNames: []string{"Maria"}, Surnames: []string{"Garcia", "Di"}}
+
+	config := DefaultScoreConfig()
+	config.Prepositions["di"] = true
+	if NewScorer(dataset, config).ScoreCombination(combo) >= NewScorer(dataset, DefaultScoreConfig()).ScoreCombination(combo) {
+		t.Error("Expected a configured preposition to be penalized as a trailing surname")
+	}
+
+	// Italian stop words are dropped from the input before scoring
+	config = DefaultScoreConfig()
+	config.StopWords = map[string]bool{"il": true, "con": true}
+	detector := NewWithConfig(dataset, config)
+	result := detector.DetectPII([]string{"Il", "Jose", "Garcia"})
+	if !equalIntSlices(result.Details.FirstNameIndices, []int{1}) || !equalIntSlices(result.Details.SurnameIndices, []int{2}) {
+		t.Errorf("Expected %q to be dropped, got indices %v and %v", "Il",
+			result.Details.FirstNameIndices, result.Details.SurnameIndices)
+	}
+	if result = New(dataset).DetectPII([]string{"Il", "Jose", "Garcia"}); len(result.Details.FirstNames)+len(result.Details.Surnames) != 3 {
+		t.Errorf("Expected %q to be kept with the default stop words, got %+v", "Il", result.Details)
+	}
+}
+
+func TestScoreConfig_WithStopWords(t *testing.T) {
+	base := DefaultScoreConfig()
+	config := base.WithStopWords(map[string]bool{"Que": true, "und": true})
+
+	if !config.StopWords["que"] || !config.StopWords["und"] || !config.StopWords["the"] {
+		t.Errorf("Expected custom words added to the English defaults, got %v", config.StopWords)
+	}
+	if base.StopWords["und"] {
+		t.Errorf("Expected the original config's stop words to be left unchanged")
+	}
+
+	detector := NewWithConfig(createTestDataset(), config)
+	result := detector.DetectPII([]string{"Jose", "und", "Garcia"})
+	if !equalStringSlices(result.Details.FirstNames, []string{"Jose"}) || !equalStringSlices(result.Details.Surnames, []string{"Garcia"}) {
+		t.Errorf("Expected %q to be dropped, got %v/%v", "und", result.Details.FirstNames, result.Details.Surnames)
+	}
+
+	spanish := StopWordsFor("es", "xx")
+	if !spanish["que"] || !spanish["the"] || spanish["de"] || spanish["und"] {
+		t.Errorf("Expected Spanish and English stop words without name particles, got %v", spanish)
+	}
+	if german := StopWordsFor("DE"); !german["und"] || german["der"] || german["von"] {
+		t.Errorf("Expected German stop words without name particles, got %v", german)
+	}
+}
+
+func TestPrepositionsFor(t *testing.T) {
+	spanish := PrepositionsFor("es")
+	if !spanish["de"] || !spanish["del"] || spanish["van"] || spanish["le"] || spanish["les"] {
+		t.Errorf("Expected only Spanish connectors, got %v", spanish)
+	}
+	if arabic := PrepositionsFor("AR", "sv", "xx"); !arabic["bin"] || !arabic["ibn"] || !arabic["af"] || len(arabic) != 5 {
+		t.Errorf("Expected Arabic and Swedish connectors, got %v", arabic)
+	}
+
+	defaults := DefaultPrepositions()
+	for _, word := range []string{"de", "da", "le", "van", "von", "of"} {
+		if !defaults[word] {
+			t.Errorf("Expected %q in the default prepositions", word)
+		}
+	}
+
+	// "bin" and "af" are also names in the dataset, so they are opt-in
+	for _, word := range []string{"bin", "ibn", "af", "av"} {
+		if defaults[word] {
+			t.Errorf("Expected %q left out of the default prepositions", word)
+		}
+	}
+}
+
+func TestPrepositions_DutchSurnameParticle(t *testing.T) {
+	dataset := createTestDataset()
+	dataset.FirstNames["JAN"] = &types.NameData{
+		Country: map[string]float32{"NL": 0.3},
+		Gender:  map[string]float32{"M": 0.97, "F": 0.03},
+		Rank:    map[string]int32{"NL": 4},
+	}
+	dataset.LastNames["DIJK"] = &types.NameData{
+		Country: map[string]float32{"NL": 0.5},
+		Rank:    map[string]int32{"NL": 12},
+	}
+	words := []string{"Jan", "van", "Dijk"}
+
+	dutch := DefaultScoreConfig()
+	dutch.Prepositions = PrepositionsFor("nl")
+	dutch.DiscountSurnameParticles = true
+	result := NewWithConfig(dataset, dutch).DetectPII(words)
+	withoutParticle := NewWithConfig(dataset, dutch).DetectPII([]string{"Jan", "Dijk"})
+	if !result.IsLikelyName || result.Confidence != withoutParticle.Confidence {
+		t.Errorf("Expected %v to score like %q (%.3f) with Dutch enabled, got %.3f",
+			words, "Jan Dijk", withoutParticle.Confidence, result.Confidence)
+	}
+	if !equalStringSlices(result.Details.Surnames, []string{"van", "Dijk"}) {
+		t.Errorf("Expected %q kept in the surname, got %v", "van", result.Details.Surnames)
+	}
+
+	// By default the particle still counts as an unknown token
+	counted := dutch
+	counted.DiscountSurnameParticles = false
+	if other := NewWithConfig(dataset, counted).DetectPII(words); other.Confidence >= result.Confidence {
+		t.Errorf("Expected %v to score lower with its particle counted, got %.3f >= %.3f",
+			words, other.Confidence, result.Confidence)
+	}
+
+	// Without Dutch, "van" is just a word the dataset doesn't know
+	spanish := dutch
+	spanish.Prepositions = PrepositionsFor("es")
+	if other := NewWithConfig(dataset, spanish).DetectPII(words); other.Confidence >= result.Confidence {
+		t.Errorf("Expected %v to score lower without Dutch connectors, got %.3f >= %.3f",
+			words, other.Confidence, result.Confidence)
+	}
+
+	// A dangling preposition is still penalized
+	scorer := NewScorer(dataset, dutch)
+	dangling := types.NameCombination{FirstNames: []string{"Jan"}, Surnames: []string{"Dijk", "van"}}
+	particle := types.NameCombination{FirstNames: []string{"Jan"}, Surnames: []string{"van", "Dijk"}}
+	if scorer.ScoreCombination(dangling) >= scorer.ScoreCombination(particle) {
+		t.Errorf("Expected a trailing %q to be penalized", "van")
+	}
+}
+
+func TestPredictGender(t *testing.T) {
+	dataset := createTestDataset()
+	dataset.FirstNames["ALEX"] = &types.NameData{
+		Country: map[string]float32{"US": 0.2},
+		Gender:  map[string]float32{"M": 0.52, "F": 0.48},
+		Rank:    map[string]int32{"US": 150},
+	}
+	dataset.FirstNames["KIM"] = &types.NameData{
+		Country: map[string]float32{"KR": 0.5},
+		Rank:    map[string]int32{"KR": 3},
+	}
+	scorer := NewScorer(dataset, DefaultScoreConfig())
+
+	tests := []struct {
+		firstNames []string
+		gender     string
+		confidence float64
+	}{
+		{[]string{"Maria"}, "Female", 0.99},
+		{[]string{"Jose"}, "Male", 0.98},
+		{[]string{"Alex"}, "Unisex", 0.52},
+		{[]string{"Jose", "Manuel"}, "Male", 0.985},
+		{[]string{"Jose", "Maria"}, "Unisex", 0.505}, // Conflicting first names
+		{[]string{"Kim"}, "Unknown", 0},
+		{[]string{"Xyzzy"}, "Unknown", 0},
+	}
+
+	for _, tt := range tests {
+		combo := types.NameCombination{FirstNames: tt.firstNames, Surnames: []string{"Smith"}}
+		gender, confidence := scorer.PredictGender(combo)
+		if gender != tt.gender || math.Abs(confidence-tt.confidence) > 1e-6 {
+			t.Errorf("%v: expected %s (%.2f), got %s (%.2f)", tt.firstNames, tt.gender, tt.confidence, gender, confidence)
+		}
+	}
+
+	// Without a margin only the leading gender counts
+	config := DefaultScoreConfig()
+	config.UnisexMargin = 0
+	alex := types.NameCombination{FirstNames: []string{"Alex"}, Surnames: []string{"Smith"}}
+	if gender := NewScorer(dataset, config).GetGender(alex); gender != "Male" {
+		t.Errorf("Expected Male with no unisex margin, got %s", gender)
+	}
+}
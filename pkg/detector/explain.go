@@ -0,0 +1,134 @@
+package detector
+
+import (
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+// ExplainPII detects a name like DetectPIIWithThreshold and breaks its
+// confidence down into the per-token scores, bonuses and pattern multipliers
+// of the winning split. Input that could not be analyzed is returned with
+// only Result and FinalScore set.
+func (d *Detector) ExplainPII(words []string, threshold float64) types.PIIExplanation {
+	result := d.DetectPIIWithThreshold(words, threshold)
+	explanation := types.PIIExplanation{
+		Result:     result,
+		FinalScore: result.Confidence,
+	}
+	if !result.Analyzed {
+		return explanation
+	}
+
+	combo := types.NameCombination{
+		FirstNames: result.Details.FirstNames,
+		Surnames:   result.Details.Surnames,
+	}
+
+	var factors []types.Factor
+	if result.Details.Pattern == usernamePatternPrefix+"initial_1_last" {
+		// The initial is not scored; the surname match is the whole score
+		combo.FirstNames = nil
+		factors = append(factors, result.Decision.Supporting...)
+	} else {
+		factors = d.scorer.Factors(combo)
+	}
+	explanation.Components = d.scorer.ComponentScores(combo)
+
+	// Factors are in scoring order: additive terms first, then the pattern
+	// multipliers, each applied to the running total
+	var running float64
+	for _, factor := range factors {
+		switch factor.Name {
+		case "gender_consistency":
+			explanation.GenderConsistencyBonus += factor.Impact
+		case "country_overlap":
+			explanation.CountryOverlapBonus += factor.Impact
+		case "role_fit":
+			explanation.RoleFitBonus += factor.Impact
+		case "multiple_names":
+			explanation.MultipleNamesBonus += factor.Impact
+		case "missing_country":
+			explanation.MissingCountryDiscount += factor.Impact
+		case "preposition_first_name", "preposition_surname", "top_pair", "noise_floor":
+			multiplier := 1.0
+			if running != 0 {
+				multiplier = (running + factor.Impact) / running
+			}
+			explanation.Adjustments = append(explanation.Adjustments, types.ScoreAdjustment{
+				Name:       factor.Name,
+				Multiplier: multiplier,
+				Reason:     factor.Detail,
+			})
+		default:
+			explanation.BaseScore += factor.Impact
+		}
+		running += factor.Impact
+	}
+	explanation.RawScore = running
+
+	return explanation
+}
+
+// multiplicativeFactors are the factors recorded as the change a pattern
+// multiplier made to the running score rather than as a term of their own
+var multiplicativeFactors = map[string]bool{
+	"preposition_first_name": true,
+	"preposition_surname":    true,
+	"top_pair":               true,
+	"noise_floor":            true,
+}
+
+// ScoreBreakdown turns the factors of a scored combination into the steps
+// of Details.ScoreBreakdown. The name_matches factor is split into the
+// base_match and popularity shares of its matched tokens; the other factors
+// keep their names, with Multiplier set for the pattern multipliers.
+func (s *Scorer) ScoreBreakdown(combo types.NameCombination, factors []types.Factor) []types.ScoreTerm {
+	terms := make([]types.ScoreTerm, 0, len(factors)+1)
+	var running float64
+	for _, factor := range factors {
+		switch {
+		case factor.Name == "name_matches":
+			terms = append(terms, s.splitNameMatches(combo, factor)...)
+		case multiplicativeFactors[factor.Name]:
+			multiplier := 1.0
+			if running != 0 {
+				multiplier = (running + factor.Impact) / running
+			}
+			terms = append(terms, types.ScoreTerm{
+				Name:       factor.Name,
+				Delta:      factor.Impact,
+				Multiplier: multiplier,
+				Detail:     factor.Detail,
+			})
+		default:
+			terms = append(terms, types.ScoreTerm{
+				Name:   factor.Name,
+				Delta:  factor.Impact,
+				Detail: factor.Detail,
+			})
+		}
+		running += factor.Impact
+	}
+	return terms
+}
+
+// splitNameMatches divides the name_matches factor between the base score
+// and the popularity bonus in the proportion the matched tokens earned them,
+// so the two terms still add up to the factor
+func (s *Scorer) splitNameMatches(combo types.NameCombination, factor types.Factor) []types.ScoreTerm {
+	var base, popularity float64
+	for _, component := range s.ComponentScores(combo) {
+		if component.Matched {
+			base += component.BaseScore
+			popularity += component.PopularityScore
+		}
+	}
+	if base+popularity == 0 {
+		return []types.ScoreTerm{{Name: "base_match", Delta: factor.Impact, Detail: factor.Detail}}
+	}
+
+	baseDelta := factor.Impact * base / (base + popularity)
+	return []types.ScoreTerm{
+		{Name: "base_match", Delta: baseDelta, Detail: "found in the dataset for their roles"},
+		{Name: "popularity", Delta: factor.Impact - baseDelta, Detail: "bonus for the rank of the matched tokens"},
+	}
+}
@@ -0,0 +1,104 @@
+package detector
+
+import (
+	"math"
+	"testing"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+func TestExplainPII(t *testing.T) {
+	detector := New(createTestDataset())
+
+	explanation := detector.ExplainPII([]string{"José", "García"}, 0.7)
+	if !explanation.Result.IsLikelyName {
+		t.Fatalf("Expected José García to be detected, got %.3f", explanation.Result.Confidence)
+	}
+
+	if len(explanation.Components) != 2 {
+		t.Fatalf("Expected 2 components, got %+v", explanation.Components)
+	}
+	for _, component := range explanation.Components {
+		if !component.Matched || component.BaseScore != 0.25 || component.PopularityScore != 0.35 {
+			t.Errorf("Expected top-ranked %s to score 0.25 base + 0.35 popularity, got %+v", component.Token, component)
+		}
+	}
+	if explanation.CountryOverlapBonus <= 0 {
+		t.Errorf("Expected a country overlap bonus, got %.3f", explanation.CountryOverlapBonus)
+	}
+
+	if len(explanation.Adjustments) != 1 || explanation.Adjustments[0].Name != "top_pair" {
+		t.Fatalf("Expected the top pair adjustment, got %+v", explanation.Adjustments)
+	}
+	if multiplier := explanation.Adjustments[0].Multiplier; math.Abs(multiplier-1.4) > 1e-9 {
+		t.Errorf("Expected the default top pair multiplier 1.4, got %.3f", multiplier)
+	}
+
+	// The parts add up to the raw score, which is capped to the confidence
+	sum := explanation.BaseScore + explanation.GenderConsistencyBonus + explanation.CountryOverlapBonus +
+		explanation.RoleFitBonus + explanation.MultipleNamesBonus + explanation.MissingCountryDiscount
+	for _, adjustment := range explanation.Adjustments {
+		sum *= adjustment.Multiplier
+	}
+	if math.Abs(sum-explanation.RawScore) > 1e-9 {
+		t.Errorf("Expected the parts to reproduce the raw score %.4f, got %.4f", explanation.RawScore, sum)
+	}
+	if explanation.FinalScore != math.Min(1.0, explanation.RawScore) || explanation.FinalScore != explanation.Result.Confidence {
+		t.Errorf("Expected final score %.4f to be the capped raw score %.4f", explanation.FinalScore, explanation.RawScore)
+	}
+}
+
+func TestExplainPII_Preposition(t *testing.T) {
+	explanation := New(createTestDataset()).ExplainPII([]string{"Jose", "Garcia", "De"}, 0.7)
+
+	if len(explanation.Adjustments) != 1 {
+		t.Fatalf("Expected a single adjustment, got %+v", explanation.Adjustments)
+	}
+	adjustment := explanation.Adjustments[0]
+	if adjustment.Name != "preposition_surname" || math.Abs(adjustment.Multiplier-0.7) > 1e-9 || adjustment.Reason == "" {
+		t.Errorf("Expected a 0.7 preposition_surname multiplier with a reason, got %+v", adjustment)
+	}
+}
+
+func TestExplainPII_InvalidLength(t *testing.T) {
+	explanation := New(createTestDataset()).ExplainPII([]string{"Jose"}, 0.7)
+	if explanation.Result.Analyzed || explanation.Components != nil || explanation.FinalScore != 0 {
+		t.Errorf("Expected an empty explanation for input that can't be analyzed, got %+v", explanation)
+	}
+}
+
+func TestDetectPII_ScoreBreakdown(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.Explain = true
+	detector := NewWithDetectorConfig(createTestDataset(), DefaultScoreConfig(), config)
+
+	result := detector.DetectPII([]string{"José", "García"})
+	steps := make(map[string]types.ScoreTerm)
+	var sum float64
+	for _, term := range result.Details.ScoreBreakdown {
+		steps[term.Name] = term
+		sum += term.Delta
+	}
+
+	// Both tokens are top ranked, so the match splits 0.25 base to 0.35 popularity
+	base, popularity := steps["base_match"], steps["popularity"]
+	if base.Delta <= 0 || math.Abs(base.Delta/popularity.Delta-0.25/0.35) > 1e-9 {
+		t.Errorf("Expected base and popularity terms in a 0.25:0.35 ratio, got %+v and %+v", base, popularity)
+	}
+	if topPair, ok := steps["top_pair"]; !ok || math.Abs(topPair.Multiplier-1.4) > 1e-9 || topPair.Delta <= 0 {
+		t.Errorf("Expected a top pair step with multiplier 1.4, got %+v", topPair)
+	}
+	if _, ok := steps["country_overlap"]; !ok {
+		t.Errorf("Expected a country overlap step, got %+v", result.Details.ScoreBreakdown)
+	}
+
+	// The deltas add up to the raw score, which is capped to the confidence
+	if math.Abs(math.Min(1.0, sum)-result.Confidence) > 1e-9 {
+		t.Errorf("Expected the deltas (sum %.4f) to reproduce confidence %.4f", sum, result.Confidence)
+	}
+
+	plain := New(createTestDataset()).DetectPII([]string{"José", "García"})
+	if plain.Details.ScoreBreakdown != nil {
+		t.Errorf("Expected no breakdown without Explain, got %+v", plain.Details.ScoreBreakdown)
+	}
+}
@@ -0,0 +1,66 @@
+package detector
+
+import (
+	"strings"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+// swapMargin is how much better the swapped fields must score before a swap
+// is suggested, so names that work either way round ("Thomas James") are
+// left alone
+const swapMargin = 0.1
+
+// DetectFirstLast scores a name entered into separate first and last name
+// fields, keeping the split the user chose. SuggestSwap is set when the
+// fields score much better the other way round, such as "Garcia" entered as
+// the first name and "Jose" as the last name.
+func (d *Detector) DetectFirstLast(first, last string) types.FirstLastResult {
+	return d.DetectFirstLastWithThreshold(first, last, 0.7) // Default threshold
+}
+
+// DetectFirstLastWithThreshold is DetectFirstLast with a custom confidence threshold
+func (d *Detector) DetectFirstLastWithThreshold(first, last string, threshold float64) types.FirstLastResult {
+	firstNames, _ := d.cleanWords(strings.Fields(first))
+	surnames, _ := d.cleanWords(strings.Fields(last))
+	if len(firstNames) == 0 || len(surnames) == 0 {
+		return types.FirstLastResult{
+			PIIResult: rejectedResult("insufficient_words", threshold),
+		}
+	}
+
+	combo := types.NameCombination{FirstNames: firstNames, Surnames: surnames}
+	swapped := types.NameCombination{FirstNames: surnames, Surnames: firstNames}
+
+	score := d.scorer.ScoreCombination(combo)
+	swappedScore := d.scorer.ScoreCombination(swapped)
+
+	result := d.buildResult(scoredResult{
+		combo:   combo,
+		score:   score,
+		factors: d.scorer.Factors(combo),
+		tokens:  append(append([]string{}, firstNames...), surnames...),
+	}, threshold)
+
+	return types.FirstLastResult{
+		PIIResult:         result,
+		SuggestSwap:       swappedScore-score >= swapMargin,
+		SwappedConfidence: swappedScore,
+	}
+}
+
+// MostLikelyOrder decides which of two tokens is the first name and which
+// is the surname by scoring both orderings. It returns the better ordering,
+// its confidence, and the margin by which it beat the other ordering; the
+// given order is kept when both score the same.
+func (d *Detector) MostLikelyOrder(a, b string) (firstName, surname string, confidence, margin float64) {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+
+	forward := d.scorer.ScoreCombination(types.NameCombination{FirstNames: []string{a}, Surnames: []string{b}})
+	reverse := d.scorer.ScoreCombination(types.NameCombination{FirstNames: []string{b}, Surnames: []string{a}})
+
+	if reverse > forward {
+		return b, a, reverse, reverse - forward
+	}
+	return a, b, forward, forward - reverse
+}
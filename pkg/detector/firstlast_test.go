@@ -0,0 +1,94 @@
+package detector
+
+import (
+	"testing"
+)
+
+func TestDetectFirstLast(t *testing.T) {
+	detector := New(createTestDataset())
+
+	tests := []struct {
+		name        string
+		first       string
+		last        string
+		suggestSwap bool
+		pattern     string
+	}{
+		{
+			name:        "Correct order",
+			first:       "Jose",
+			last:        "Garcia",
+			suggestSwap: false,
+			pattern:     "1_first_1_last",
+		},
+		{
+			name:        "Swapped fields",
+			first:       "Garcia",
+			last:        "Jose",
+			suggestSwap: true,
+			pattern:     "1_first_1_last",
+		},
+		{
+			name:        "Swapped compound fields",
+			first:       "Garcia Lopez",
+			last:        "Jose Manuel",
+			suggestSwap: true,
+			pattern:     "2_first_2_last",
+		},
+		{
+			name:        "Empty last name",
+			first:       "Jose",
+			last:        "  ",
+			suggestSwap: false,
+			pattern:     "insufficient_words",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := detector.DetectFirstLast(tt.first, tt.last)
+
+			if result.SuggestSwap != tt.suggestSwap {
+				t.Errorf("Expected SuggestSwap=%v, got %v (confidence %.3f, swapped %.3f)",
+					tt.suggestSwap, result.SuggestSwap, result.Confidence, result.SwappedConfidence)
+			}
+			if result.Details.Pattern != tt.pattern {
+				t.Errorf("Expected pattern %s, got %s", tt.pattern, result.Details.Pattern)
+			}
+			if tt.suggestSwap && result.SwappedConfidence <= result.Confidence {
+				t.Errorf("Expected swapped confidence %.3f to exceed %.3f",
+					result.SwappedConfidence, result.Confidence)
+			}
+		})
+	}
+}
+
+func TestMostLikelyOrder(t *testing.T) {
+	detector := New(createTestDataset())
+
+	tests := []struct {
+		a, b        string
+		first, last string
+		decisive    bool
+	}{
+		{"Jose", "Garcia", "Jose", "Garcia", true},
+		{"Garcia", "Jose", "Jose", "Garcia", true},
+		{" Smith ", "John", "John", "Smith", true},
+		{"Xyzzy", "Qwerty", "Xyzzy", "Qwerty", false},
+	}
+
+	for _, tt := range tests {
+		first, last, confidence, margin := detector.MostLikelyOrder(tt.a, tt.b)
+
+		if first != tt.first || last != tt.last {
+			t.Errorf("MostLikelyOrder(%q, %q): expected %s/%s, got %s/%s", tt.a, tt.b, tt.first, tt.last, first, last)
+		}
+		if margin < 0 {
+			t.Errorf("MostLikelyOrder(%q, %q): expected non-negative margin, got %.3f", tt.a, tt.b, margin)
+		}
+		if tt.decisive && (confidence <= 0 || margin <= 0) {
+			t.Errorf("MostLikelyOrder(%q, %q): expected positive confidence and margin, got %.3f and %.3f",
+				tt.a, tt.b, confidence, margin)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package detector
+
+import (
+	"sort"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+// FuzzyMatch configures the matching of misspelled names by edit distance.
+// Distances count insertions, deletions, substitutions and swaps of adjacent
+// letters, so "Jhon" is one edit from "John" and "Gracia" one from "Garcia".
+type FuzzyMatch struct {
+	MaxDistance int     // Largest edit distance accepted; zero disables fuzzy matching
+	MaxRank     int32   // Only names ranked at or better than this somewhere are candidates
+	Discount    float64 // Fraction of a name's match score removed per edit
+}
+
+// DefaultFuzzyMatch returns fuzzy matching of names one edit away from a name
+// in the top 1000 of some country, earning three quarters of its score
+func DefaultFuzzyMatch() FuzzyMatch {
+	return FuzzyMatch{MaxDistance: 1, MaxRank: rareRankThreshold, Discount: 0.25}
+}
+
+// fuzzyMinLength is the shortest token, in letters, that is fuzzy matched.
+// Most short names are a single edit away from several others.
+const fuzzyMinLength = 4
+
+// fuzzyMemoSize bounds the fuzzy lookups remembered per index. Every split
+// of an input looks its tokens up several times, so remembering recent
+// results keeps the search to about once per unknown token.
+const fuzzyMemoSize = 4096
+
+// weight returns the fraction of a match's score earned at the given edit
+// distance
+func (f FuzzyMatch) weight(distance int) float64 {
+	if distance == 0 {
+		return 1.0
+	}
+	return max(0.0, 1.0-f.Discount*float64(distance))
+}
+
+// fuzzyBucket groups index keys by first letter and length. A misspelling
+// is assumed to keep the first letter, so only the buckets of nearby lengths
+// are searched.
+type fuzzyBucket struct {
+	first  rune
+	length int
+}
+
+// fuzzyCandidate is an indexed dataset key, kept as runes for comparison
+type fuzzyCandidate struct {
+	key   string
+	runes []rune
+}
+
+// fuzzyResult is a remembered fuzzy lookup
+type fuzzyResult struct {
+	nameData *types.NameData
+	distance int
+}
+
+// fuzzyMemoKey identifies a remembered fuzzy lookup
+type fuzzyMemoKey struct {
+	key         string
+	isFirstName bool
+}
+
+// fuzzyIndex holds the fuzzy match candidates of one dataset
+type fuzzyIndex struct {
+	dataset    *types.NameDataset
+	firstNames map[fuzzyBucket][]fuzzyCandidate
+	surnames   map[fuzzyBucket][]fuzzyCandidate
+
+	mu   sync.Mutex
+	memo map[fuzzyMemoKey]fuzzyResult // Cleared when it reaches fuzzyMemoSize
+}
+
+// fuzzyIndexFor returns the index of dataset, building it on first use and
+// again after the dataset is replaced
+func (s *Scorer) fuzzyIndexFor(dataset *types.NameDataset) *fuzzyIndex {
+	if index := s.fuzzy.Load(); index != nil && index.dataset == dataset {
+		return index
+	}
+
+	s.fuzzyMu.Lock()
+	defer s.fuzzyMu.Unlock()
+	if index := s.fuzzy.Load(); index != nil && index.dataset == dataset {
+		return index
+	}
+
+	maxRank := s.config.Fuzzy.MaxRank
+	if maxRank <= 0 {
+		maxRank = rareRankThreshold
+	}
+	index := &fuzzyIndex{
+		dataset:    dataset,
+		firstNames: s.bucketKeys(dataset.FirstNames, maxRank),
+		surnames:   s.bucketKeys(dataset.LastNames, maxRank),
+		memo:       make(map[fuzzyMemoKey]fuzzyResult),
+	}
+	s.fuzzy.Store(index)
+	return index
+}
+
+// bucketKeys groups the keys of names ranked at or better than maxRank by
+// fuzzyBucket, sorted within each bucket so results are deterministic
+func (s *Scorer) bucketKeys(names map[string]*types.NameData, maxRank int32) map[fuzzyBucket][]fuzzyCandidate {
+	buckets := make(map[fuzzyBucket][]fuzzyCandidate)
+	for key, nameData := range names {
+		if s.getMinRankFromData(nameData) > maxRank {
+			continue
+		}
+		first, _ := utf8.DecodeRuneInString(key)
+		bucket := fuzzyBucket{first: first, length: utf8.RuneCountInString(key)}
+		buckets[bucket] = append(buckets[bucket], fuzzyCandidate{key: key, runes: []rune(key)})
+	}
+	for _, candidates := range buckets {
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].key < candidates[j].key })
+	}
+	return buckets
+}
+
+// fuzzyLookup finds the indexed name closest to key within
+// ScoreConfig.Fuzzy.MaxDistance, preferring the better-ranked name on a tie
+func (s *Scorer) fuzzyLookup(dataset *types.NameDataset, key string, isFirstName bool) (*types.NameData, string, int) {
+	runes := []rune(key)
+	if len(runes) < fuzzyMinLength {
+		return nil, "", 0
+	}
+
+	index := s.fuzzyIndexFor(dataset)
+	memoKey := fuzzyMemoKey{key: key, isFirstName: isFirstName}
+	index.mu.Lock()
+	result, remembered := index.memo[memoKey]
+	index.mu.Unlock()
+
+	if !remembered {
+		result = s.searchFuzzy(index, runes, isFirstName)
+		index.mu.Lock()
+		if len(index.memo) >= fuzzyMemoSize {
+			clear(index.memo)
+		}
+		index.memo[memoKey] = result
+		index.mu.Unlock()
+	}
+
+	if result.nameData == nil {
+		return nil, "", 0
+	}
+	return result.nameData, lookupFuzzy, result.distance
+}
+
+// searchFuzzy scans the index buckets that can hold a name within
+// ScoreConfig.Fuzzy.MaxDistance of runes
+func (s *Scorer) searchFuzzy(index *fuzzyIndex, runes []rune, isFirstName bool) fuzzyResult {
+	buckets, targetMap := index.surnames, index.dataset.LastNames
+	if isFirstName {
+		buckets, targetMap = index.firstNames, index.dataset.FirstNames
+	}
+
+	maxDistance := s.config.Fuzzy.MaxDistance
+	best := fuzzyResult{distance: maxDistance + 1}
+	for length := len(runes) - maxDistance; length <= len(runes)+maxDistance; length++ {
+		for _, candidate := range buckets[fuzzyBucket{first: runes[0], length: length}] {
+			distance := editDistance(runes, candidate.runes, best.distance+1)
+			if distance > maxDistance || distance > best.distance {
+				continue
+			}
+			nameData := targetMap[candidate.key]
+			if distance < best.distance || s.getMinRankFromData(nameData) < s.getMinRankFromData(best.nameData) {
+				best = fuzzyResult{nameData: nameData, distance: distance}
+			}
+		}
+	}
+
+	if best.nameData == nil {
+		return fuzzyResult{}
+	}
+	return best
+}
+
+// editDistance returns the optimal string alignment distance between a and
+// b: the insertions, deletions, substitutions and adjacent swaps needed to
+// turn one into the other. It stops early and returns limit once the
+// distance is known to be at least limit.
+func editDistance(a, b []rune, limit int) int {
+	if diff := len(a) - len(b); diff >= limit || -diff >= limit {
+		return limit
+	}
+
+	// Three rows of the dynamic programming table: two back, previous and current
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin >= limit {
+			return limit
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return min(prev[len(b)], limit)
+}
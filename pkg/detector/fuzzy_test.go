@@ -0,0 +1,63 @@
+package detector
+
+import "testing"
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"JOHN", "JOHN", 0},
+		{"JHON", "JOHN", 1}, // Adjacent swap
+		{"GRACIA", "GARCIA", 1},
+		{"SMIT", "SMITH", 1},  // Deletion
+		{"SMYTH", "SMITH", 1}, // Substitution
+		{"GRACIAS", "GARCIA", 2},
+		{"KITTEN", "SITTING", 3},
+	}
+
+	for _, test := range tests {
+		if got := editDistance([]rune(test.a), []rune(test.b), 10); got != test.expected {
+			t.Errorf("editDistance(%q, %q): expected %d, got %d", test.a, test.b, test.expected, got)
+		}
+	}
+
+	if got := editDistance([]rune("KITTEN"), []rune("SITTING"), 2); got != 2 {
+		t.Errorf("Expected the distance to stop at the limit of 2, got %d", got)
+	}
+}
+
+func TestDetectPII_Fuzzy(t *testing.T) {
+	config := DefaultScoreConfig()
+	config.Fuzzy = DefaultFuzzyMatch()
+	fuzzy := NewWithConfig(createTestDataset(), config)
+	exact := New(createTestDataset())
+
+	if result := exact.DetectPII([]string{"Jhon", "Smith"}); result.IsLikelyName {
+		t.Errorf("Expected \"Jhon Smith\" to fail without fuzzy matching, got %.3f", result.Confidence)
+	}
+
+	result := fuzzy.DetectPII([]string{"Jhon", "Smith"})
+	if !result.IsLikelyName {
+		t.Fatalf("Expected \"Jhon Smith\" to be a likely name with fuzzy matching, got %.3f", result.Confidence)
+	}
+	if len(result.Details.Matches) == 0 || result.Details.Matches[0].Lookup != lookupFuzzy {
+		t.Errorf("Expected \"Jhon\" to be reported as a fuzzy match, got %+v", result.Details.Matches)
+	}
+
+	// A misspelling scores below the correctly spelled name
+	if correct := fuzzy.DetectPII([]string{"John", "Smith"}); result.Confidence >= correct.Confidence {
+		t.Errorf("Expected the fuzzy match (%.3f) to score below the exact one (%.3f)", result.Confidence, correct.Confidence)
+	}
+
+	if result := fuzzy.DetectPII([]string{"Jose", "Gracia"}); !result.IsLikelyName {
+		t.Errorf("Expected \"Jose Gracia\" to be a likely name with fuzzy matching, got %.3f", result.Confidence)
+	}
+
+	// Rare names and short tokens are never fuzzy candidates
+	for _, name := range []string{"Hermosa", "Jo"} {
+		if _, exists := fuzzy.scorer.lookup(name, false); exists {
+			t.Errorf("Expected no fuzzy match for %q", name)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package detector
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+// DetectionHints carries prior knowledge about the person, such as from a
+// record-linkage context, to fuse with the name-based evidence. Empty fields
+// are ignored.
+type DetectionHints struct {
+	Country string // Expected country code, e.g. "ES"
+	Gender  string // Expected gender: "M"/"F" or "Male"/"Female"
+}
+
+// DetectWithHints analyzes words like DetectPII, then raises the confidence
+// when the winning names agree with the hints and slightly lowers it when
+// they contradict them. Hints don't apply to single-token username input.
+func (d *Detector) DetectWithHints(words []string, hints DetectionHints) types.PIIResult {
+	return d.DetectWithHintsAndThreshold(words, hints, 0.7) // Default threshold
+}
+
+// DetectWithHintsAndThreshold is DetectWithHints with a custom confidence threshold
+func (d *Detector) DetectWithHintsAndThreshold(words []string, hints DetectionHints, threshold float64) types.PIIResult {
+	return d.detect(words, threshold, &hints)
+}
+
+// hintFactors scores a combination against the hints. The country hint is
+// weighed by the fraction of matched names associated with that country, and
+// the gender hint by the average probability of that gender across first names.
+func (s *Scorer) hintFactors(combo types.NameCombination, hints DetectionHints) []types.Factor {
+	var factors []types.Factor
+
+	if country := strings.ToUpper(strings.TrimSpace(hints.Country)); country != "" {
+		var matched, inCountry int
+		for _, side := range []struct {
+			names       []string
+			isFirstName bool
+		}{{combo.FirstNames, true}, {combo.Surnames, false}} {
+			for _, name := range side.names {
+				nameData, exists := s.lookup(name, side.isFirstName)
+				if !exists {
+					continue
+				}
+				matched++
+				if nameData.Country[country] > 0 || nameData.Rank[country] > 0 {
+					inCountry++
+				}
+			}
+		}
+
+		switch {
+		case matched == 0:
+		case inCountry > 0:
+			factors = append(factors, types.Factor{
+				Name:   "hint_country",
+				Impact: s.config.HintBonus * float64(inCountry) / float64(matched),
+				Detail: fmt.Sprintf("%d of %d names are used in hinted country %s", inCountry, matched, country),
+			})
+		default:
+			factors = append(factors, types.Factor{
+				Name:   "hint_country",
+				Impact: -s.config.HintPenalty,
+				Detail: fmt.Sprintf("no name is used in hinted country %s", country),
+			})
+		}
+	}
+
+	if gender := normalizeGenderHint(hints.Gender); gender != "" {
+		var total float64
+		var count int
+		for _, name := range combo.FirstNames {
+			if nameData, exists := s.lookup(name, true); exists && len(nameData.Gender) > 0 {
+				total += float64(nameData.Gender[gender])
+				count++
+			}
+		}
+
+		if count > 0 {
+			agreement := total / float64(count)
+			factor := types.Factor{
+				Name:   "hint_gender",
+				Detail: fmt.Sprintf("first names are %.0f%% %s, as hinted", agreement*100, gender),
+			}
+			if agreement >= 0.5 {
+				factor.Impact = s.config.HintBonus * agreement
+			} else {
+				factor.Impact = -s.config.HintPenalty * (1 - agreement)
+				factor.Detail = fmt.Sprintf("first names are only %.0f%% %s, against the hint", agreement*100, gender)
+			}
+			factors = append(factors, factor)
+		}
+	}
+
+	return factors
+}
+
+// normalizeGenderHint maps a gender hint to the dataset's "M"/"F" keys
+func normalizeGenderHint(gender string) string {
+	switch strings.ToUpper(strings.TrimSpace(gender)) {
+	case "M", "MALE":
+		return "M"
+	case "F", "FEMALE":
+		return "F"
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package detector
+
+import (
+	"testing"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+func TestDetectWithHints(t *testing.T) {
+	detector := New(createTestDataset())
+	words := []string{"Maria", "Xyzzy"}
+	base := detector.DetectPII(words)
+
+	tests := []struct {
+		name   string
+		hints  DetectionHints
+		effect int // 1 raises, -1 lowers, 0 leaves confidence unchanged
+	}{
+		{name: "No hints", hints: DetectionHints{}, effect: 0},
+		{name: "Matching country", hints: DetectionHints{Country: "es"}, effect: 1},
+		{name: "Matching gender", hints: DetectionHints{Gender: "Female"}, effect: 1},
+		{name: "Contradicting country", hints: DetectionHints{Country: "JP"}, effect: -1},
+		{name: "Contradicting gender", hints: DetectionHints{Gender: "M"}, effect: -1},
+		{name: "Unrecognized gender", hints: DetectionHints{Gender: "unknown"}, effect: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := detector.DetectWithHints(words, tt.hints)
+
+			switch {
+			case tt.effect > 0 && result.Confidence <= base.Confidence:
+				t.Errorf("Expected confidence above %.3f, got %.3f", base.Confidence, result.Confidence)
+			case tt.effect < 0 && result.Confidence >= base.Confidence:
+				t.Errorf("Expected confidence below %.3f, got %.3f", base.Confidence, result.Confidence)
+			case tt.effect == 0 && result.Confidence != base.Confidence:
+				t.Errorf("Expected unchanged confidence %.3f, got %.3f", base.Confidence, result.Confidence)
+			}
+
+			if tt.effect != 0 && !hasHintFactor(append(result.Decision.Supporting, result.Decision.Detracting...)) {
+				t.Errorf("Expected a hint factor in the decision, got %+v", result.Decision)
+			}
+		})
+	}
+}
+
+func hasHintFactor(factors []types.Factor) bool {
+	for _, factor := range factors {
+		if factor.Name == "hint_country" || factor.Name == "hint_gender" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package detector
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// RenderHTML scans text for names with the default scan options and returns
+// it as an HTML fragment in which each detected name is wrapped in
+// <mark data-confidence="0.82">...</mark>. All other text is escaped.
+func (d *Detector) RenderHTML(text string) string {
+	return d.RenderHTMLWithOptions(text, DefaultScanOptions())
+}
+
+// RenderHTMLWithOptions is RenderHTML with custom scan options
+func (d *Detector) RenderHTMLWithOptions(text string, opts ScanOptions) string {
+	var b strings.Builder
+	b.Grow(len(text))
+
+	offset := 0
+	for _, match := range d.ScanText(text, opts) {
+		b.WriteString(html.EscapeString(text[offset:match.Start]))
+		fmt.Fprintf(&b, `<mark data-confidence="%.2f">%s</mark>`,
+			match.Result.Confidence, html.EscapeString(text[match.Start:match.End]))
+		offset = match.End
+	}
+	b.WriteString(html.EscapeString(text[offset:]))
+
+	return b.String()
+}
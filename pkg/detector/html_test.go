@@ -0,0 +1,28 @@
+package detector
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderHTML(t *testing.T) {
+	detector := New(createTestDataset())
+
+	text := "<b>Call</b> José García & the team"
+	rendered := detector.RenderHTML(text)
+
+	if !strings.HasPrefix(rendered, "&lt;b&gt;Call&lt;/b&gt; <mark data-confidence=\"") {
+		t.Errorf("Expected escaped prefix before the mark, got %q", rendered)
+	}
+	if !strings.Contains(rendered, "\">José García</mark>") {
+		t.Errorf("Expected José García to be highlighted, got %q", rendered)
+	}
+	if !strings.HasSuffix(rendered, "</mark> &amp; the team") {
+		t.Errorf("Expected escaped suffix after the mark, got %q", rendered)
+	}
+
+	plain := "no names <here>"
+	if got := detector.RenderHTML(plain); got != "no names &lt;here&gt;" {
+		t.Errorf("Expected only escaping without names, got %q", got)
+	}
+}
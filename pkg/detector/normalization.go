@@ -2,20 +2,38 @@ package detector
 
 import (
 	"strings"
+	"sync"
 	"unicode"
+	"unicode/utf8"
+
 	"golang.org/x/text/runes"
 	"golang.org/x/text/transform"
 	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
 )
 
+// accentRemovers pools the transform chains used by normalizeAccents. A chain
+// allocates sizable buffers and keeps state while running, so each goroutine
+// takes its own from the pool instead of building one per call.
+var accentRemovers = sync.Pool{
+	New: func() any {
+		return transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	},
+}
+
 // normalizeAccents removes accents and diacritical marks from a string
 // Example: "José García" -> "Jose Garcia"
 func normalizeAccents(s string) string {
-	// Create a transformer that removes accents by decomposing Unicode characters
-	// and then removing the combining diacritical marks
-	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
-	
-	// Apply the transformation
+	// ASCII has no marks to remove
+	if isASCII(s) {
+		return s
+	}
+
+	// Decompose Unicode characters and remove the combining diacritical marks
+	t := accentRemovers.Get().(transform.Transformer)
+	defer accentRemovers.Put(t)
+
+	// Apply the transformation; String resets the chain first
 	result, _, err := transform.String(t, s)
 	if err != nil {
 		// If transformation fails, return original string
@@ -25,10 +43,317 @@ func normalizeAccents(s string) string {
 	return result
 }
 
+// isASCII reports whether s contains only ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
 // normalizeForLookup normalizes a name for database lookup
-// This applies both accent normalization and case normalization
+// This applies width, accent and case normalization
+// Example: "Ｊｏｓé" (full-width) -> "JOSE"
 func normalizeForLookup(name string) string {
-	// First normalize accents, then trim and convert to uppercase
-	normalized := normalizeAccents(name)
+	// ASCII needs neither narrowing nor accent removal
+	if isASCII(name) {
+		return strings.ToUpper(strings.TrimSpace(name))
+	}
+
+	// First narrow full-width Latin and normalize accents, then trim and
+	// convert to uppercase
+	normalized := normalizeAccents(width.Narrow.String(name))
 	return strings.ToUpper(strings.TrimSpace(normalized))
-}
\ No newline at end of file
+}
+
+// stripNamePunctuation removes periods and apostrophes from a lookup key and
+// collapses the remaining whitespace
+// Example: "ST. JOHN" -> "ST JOHN", "O'BRIEN" -> "OBRIEN"
+func stripNamePunctuation(key string) string {
+	if !strings.ContainsAny(key, ".'") {
+		return key
+	}
+
+	stripped := strings.NewReplacer(".", "", "'", "").Replace(key)
+	return strings.Join(strings.Fields(stripped), " ")
+}
+
+// splitCompoundName splits a lookup key on hyphens and apostrophes into its
+// constituent names, dropping one-letter elisions such as the "D" of
+// "D'ANGELO". It returns nil when the key has no such separator.
+// Example: "ANNE-MARIE" -> ["ANNE", "MARIE"], "D'ANGELO" -> ["ANGELO"]
+func splitCompoundName(key string) []string {
+	if !strings.ContainsAny(key, "-'’") {
+		return nil
+	}
+
+	var parts []string
+	for _, part := range strings.FieldsFunc(key, func(r rune) bool {
+		return r == '-' || r == '\'' || r == '’'
+	}) {
+		if part = strings.TrimSpace(part); utf8.RuneCountInString(part) > 1 {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// normalizeTokens returns a copy of tokens converted to Unicode NFC form
+// Example: "Jose\u0301" (NFD) -> "José"
+func normalizeTokens(tokens []string) []string {
+	if tokens == nil {
+		return nil
+	}
+
+	normalized := make([]string, len(tokens))
+	for i, token := range tokens {
+		normalized[i] = norm.NFC.String(token)
+	}
+
+	return normalized
+}
+
+// caseNames title-cases first names and surnames for display when
+// DetectorConfig.NormalizeCase is set. Surname particles such as "de" in
+// "de la Cruz" or "van" in "van Dijk" are lowercased instead.
+func (d *Detector) caseNames(firstNames, surnames []string) ([]string, []string) {
+	if !d.config.NormalizeCase {
+		return firstNames, surnames
+	}
+
+	cased := func(tokens []string, isSurname bool) []string {
+		if tokens == nil {
+			return nil
+		}
+		result := make([]string, len(tokens))
+		for i, token := range tokens {
+			if isSurname && d.scorer.isProbablyPreposition(token) && d.scorer.isSurnameParticle(tokens, i) {
+				result[i] = d.scorer.profile.toLower(token)
+			} else {
+				result[i] = d.scorer.profile.titleCase(token)
+			}
+		}
+		return result
+	}
+
+	return cased(firstNames, false), cased(surnames, true)
+}
+
+// LocaleProfile holds the character validation and casing rules for a locale.
+// The zero value applies the default Unicode rules.
+type LocaleProfile struct {
+	// Casing overrides the default Unicode case mapping, e.g. unicode.TurkishCase
+	// maps "i" to "İ" and "ı" to "I" where a naive ToUpper maps both to "I"
+	Casing unicode.SpecialCase
+
+	// Scripts restricts the letters accepted in a name word; nil accepts any letter
+	Scripts []*unicode.RangeTable
+
+	// Punctuation lists the non-letter characters allowed inside a name word
+	Punctuation string
+
+	// Folding maps letters that have no Unicode decomposition to the form used
+	// for accent-insensitive lookup (e.g. Vietnamese "Đ" -> "D")
+	Folding map[rune]rune
+
+	// Transliteration maps lowercase letters of other scripts to Latin
+	// ("ж" -> "zh"), applied before lookup so "Иван" matches "IVAN"
+	Transliteration map[rune]string
+}
+
+// latinTransliterations maps lowercase Cyrillic and Greek letters to Latin.
+// Cyrillic follows a simplified BGN/PCGN romanization and Greek ELOT 743,
+// letter by letter. Where romanizations disagree the table picks the
+// spelling most common in Latin-script name lists: "ё" is "e" like "е", as
+// "Пётр" is usually written "Petr" and Russian text often drops the dots
+// anyway, "е" is "e" even at the start of a word rather than "ye", "х" is
+// "kh" and "й" is "y". Greek digraphs are not special-cased, so "ου" becomes
+// "oy" rather than "ou". Override any of these with
+// ScoreConfig.Transliterations.
+var latinTransliterations = map[rune]string{
+	// Russian
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	// Ukrainian and Belarusian
+	'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g", 'ў': "u",
+	// Serbian and Macedonian
+	'ђ': "dj", 'ј': "j", 'љ': "lj", 'њ': "nj", 'ћ': "c", 'џ': "dz",
+	'ѓ': "gj", 'ќ': "kj", 'ѕ': "dz",
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+	'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+	'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+	'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
+
+// LatinTransliterations returns a copy of the built-in Cyrillic and Greek
+// table used by TransliterateToLatin, as a starting point for a custom
+// LocaleProfile.Transliteration
+func LatinTransliterations() map[rune]string {
+	table := make(map[rune]string, len(latinTransliterations))
+	for r, latin := range latinTransliterations {
+		table[r] = latin
+	}
+	return table
+}
+
+// TransliterateToLatin converts Cyrillic and Greek letters to Latin, keeping
+// their case and leaving other characters as they are. Use it on dataset keys
+// to match the lookups of a profile with transliteration enabled.
+// Example: "Иван" -> "Ivan", "Γιώργος" -> "Giorgos"
+func TransliterateToLatin(s string) string {
+	return transliterate(s, latinTransliterations)
+}
+
+// transliterate replaces each letter found in table, looking up accented
+// letters ("ώ") by their base letter when they have no entry of their own
+func transliterate(s string, table map[rune]string) string {
+	runes := []rune(norm.NFC.String(s))
+
+	var b strings.Builder
+	for i, r := range runes {
+		lower := unicode.ToLower(r)
+		latin, exists := table[lower]
+		if !exists {
+			base, _ := utf8.DecodeRuneInString(norm.NFD.String(string(lower)))
+			latin, exists = table[base]
+		}
+		if !exists {
+			b.WriteRune(r)
+			continue
+		}
+
+		switch {
+		case !unicode.IsUpper(r) || latin == "":
+			b.WriteString(latin)
+		case i+1 < len(runes) && unicode.IsUpper(runes[i+1]):
+			// All-caps input stays all caps: "ЖАННА" -> "ZHANNA"
+			b.WriteString(strings.ToUpper(latin))
+		default:
+			first, size := utf8.DecodeRuneInString(latin)
+			b.WriteRune(unicode.ToUpper(first))
+			b.WriteString(latin[size:])
+		}
+	}
+
+	return b.String()
+}
+
+// localeProfiles holds the built-in profiles keyed by base language code
+var localeProfiles = map[string]LocaleProfile{
+	"tr": {Casing: unicode.TurkishCase, Scripts: []*unicode.RangeTable{unicode.Latin}, Punctuation: "-'."},
+	"az": {Casing: unicode.AzeriCase, Scripts: []*unicode.RangeTable{unicode.Latin}, Punctuation: "-'."},
+	"vi": {Scripts: []*unicode.RangeTable{unicode.Latin}, Punctuation: "-'.", Folding: map[rune]rune{'Đ': 'D', 'đ': 'd'}},
+	"ru": {Punctuation: "-'.", Transliteration: latinTransliterations},
+	"uk": {Punctuation: "-'.", Transliteration: latinTransliterations},
+	"be": {Punctuation: "-'.", Transliteration: latinTransliterations},
+	"bg": {Punctuation: "-'.", Transliteration: latinTransliterations},
+	"sr": {Punctuation: "-'.", Transliteration: latinTransliterations},
+	"mk": {Punctuation: "-'.", Transliteration: latinTransliterations},
+	"el": {Punctuation: "-'.", Transliteration: latinTransliterations},
+}
+
+// GetLocaleProfile returns the profile for a locale hint such as "tr" or
+// "tr-TR". Unknown or empty locales get the default profile.
+func GetLocaleProfile(locale string) LocaleProfile {
+	base := strings.ToLower(locale)
+	if i := strings.IndexAny(base, "-_"); i >= 0 {
+		base = base[:i]
+	}
+
+	if profile, exists := localeProfiles[base]; exists {
+		return profile
+	}
+
+	return LocaleProfile{Punctuation: "-'."}
+}
+
+// isValidRune reports whether r may appear in a name word
+func (p LocaleProfile) isValidRune(r rune) bool {
+	// Combining marks are allowed so decomposed (NFD) input is accepted
+	if unicode.Is(unicode.Mn, r) || strings.ContainsRune(p.Punctuation, r) {
+		return true
+	}
+	if !unicode.IsLetter(r) {
+		return false
+	}
+
+	return len(p.Scripts) == 0 || unicode.IsOneOf(p.Scripts, r)
+}
+
+// toUpper converts s to uppercase using the profile's casing rules
+func (p LocaleProfile) toUpper(s string) string {
+	if p.Casing != nil {
+		return strings.ToUpperSpecial(p.Casing, s)
+	}
+	return strings.ToUpper(s)
+}
+
+// toLower converts s to lowercase using the profile's casing rules
+func (p LocaleProfile) toLower(s string) string {
+	if p.Casing != nil {
+		return strings.ToLowerSpecial(p.Casing, s)
+	}
+	return strings.ToLower(s)
+}
+
+// titleCase capitalizes an all-lowercase or all-uppercase word at its start
+// and after each hyphen, apostrophe or period, using the profile's casing
+// rules. Words already in mixed case, such as "McDonald", are returned as is.
+// Example: "garcía-lópez" -> "García-López", "O'BRIEN" -> "O'Brien"
+func (p LocaleProfile) titleCase(word string) string {
+	lower, upper := p.toLower(word), p.toUpper(word)
+	if word != lower && word != upper {
+		return word
+	}
+
+	var b strings.Builder
+	capitalize := true
+	for _, r := range lower {
+		switch {
+		case capitalize && unicode.IsLetter(r):
+			if p.Casing != nil {
+				r = p.Casing.ToTitle(r)
+			} else {
+				r = unicode.ToTitle(r)
+			}
+			capitalize = false
+		case strings.ContainsRune("-'’.", r):
+			capitalize = true
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// normalizeForLookup normalizes a name for database lookup using the
+// profile's transliteration, casing and folding rules
+// Example (tr): "istanbul" -> "ISTANBUL" via "İSTANBUL", "ışık" -> "ISIK"
+// Example (ru): "Иван" -> "IVAN"
+func (p LocaleProfile) normalizeForLookup(name string) string {
+	if len(p.Transliteration) > 0 {
+		name = transliterate(name, p.Transliteration)
+	}
+	if p.Casing == nil && len(p.Folding) == 0 {
+		return normalizeForLookup(name)
+	}
+
+	// Case first so locale-specific letters map correctly before their
+	// marks are removed
+	normalized := normalizeAccents(p.toUpper(strings.TrimSpace(width.Narrow.String(name))))
+	if len(p.Folding) > 0 {
+		normalized = strings.Map(func(r rune) rune {
+			if folded, exists := p.Folding[r]; exists {
+				return folded
+			}
+			return r
+		}, normalized)
+	}
+
+	return normalized
+}
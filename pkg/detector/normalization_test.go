@@ -1,7 +1,10 @@
 package detector
 
 import (
+	"fmt"
 	"testing"
+
+	"github.com/montevive/go-name-detector/pkg/types"
 )
 
 func TestNormalizeAccents(t *testing.T) {
@@ -50,6 +53,8 @@ func TestNormalizeForLookup(t *testing.T) {
 		{"JOSÉ", "JOSE"},
 		{"María García", "MARIA GARCIA"},
 		{"John Smith", "JOHN SMITH"},
+		{"Ｊｏｓｅ", "JOSE"}, // Full-width Latin
+		{"Ｇａｒｃíａ", "GARCIA"},
 	}
 
 	for _, tt := range tests {
@@ -60,6 +65,269 @@ func TestNormalizeForLookup(t *testing.T) {
 	}
 }
 
+func TestSplitCompoundName(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected []string
+	}{
+		{"ANNE-MARIE", []string{"ANNE", "MARIE"}},
+		{"LLOYD-WEBBER", []string{"LLOYD", "WEBBER"}},
+		{"D'ANGELO", []string{"ANGELO"}},
+		{"N’DIAYE", []string{"DIAYE"}},
+		{"GARCIA", nil},
+		{"-", nil},
+	}
+
+	for _, tt := range tests {
+		if result := splitCompoundName(tt.key); !equalStringSlices(result, tt.expected) {
+			t.Errorf("splitCompoundName(%q) = %v, expected %v", tt.key, result, tt.expected)
+		}
+	}
+}
+
+func TestDetectPII_NormalizeOutput(t *testing.T) {
+	dataset := createTestDataset()
+	words := []string{"Jose\u0301", "Garci\u0301a"} // NFD input
+
+	config := DefaultDetectorConfig()
+	config.NormalizeOutput = true
+	detector := NewWithDetectorConfig(dataset, DefaultScoreConfig(), config)
+
+	result := detector.DetectPII(words)
+	if !equalStringSlices(result.Details.FirstNames, []string{"José"}) {
+		t.Errorf("Expected NFC first names [José], got %q", result.Details.FirstNames)
+	}
+	if !equalStringSlices(result.Details.Surnames, []string{"García"}) {
+		t.Errorf("Expected NFC surnames [García], got %q", result.Details.Surnames)
+	}
+
+	// Without the option the raw input form is echoed back
+	result = New(dataset).DetectPII(words)
+	if !equalStringSlices(result.Details.FirstNames, []string{"Jose\u0301"}) {
+		t.Errorf("Expected raw first names, got %q", result.Details.FirstNames)
+	}
+}
+
+func TestDetectPII_NormalizeCase(t *testing.T) {
+	config := DefaultDetectorConfig()
+	config.NormalizeCase = true
+	detector := NewWithDetectorConfig(createTestDataset(), DefaultScoreConfig(), config)
+
+	tests := []struct {
+		words      []string
+		firstNames []string
+		surnames   []string
+	}{
+		{[]string{"jose", "manuel", "garcia", "lopez"}, []string{"Jose", "Manuel"}, []string{"Garcia", "Lopez"}},
+		{[]string{"JOSÉ", "garcía"}, []string{"José"}, []string{"García"}},
+		{[]string{"maria", "de", "la", "garcia"}, []string{"Maria"}, []string{"de", "la", "Garcia"}},
+		{[]string{"Maria", "De", "La", "Garcia"}, []string{"Maria"}, []string{"de", "la", "Garcia"}},
+	}
+
+	for _, test := range tests {
+		result := detector.DetectPII(test.words)
+		if !equalStringSlices(result.Details.FirstNames, test.firstNames) || !equalStringSlices(result.Details.Surnames, test.surnames) {
+			t.Errorf("%q: expected %q %q, got %q %q", test.words, test.firstNames, test.surnames,
+				result.Details.FirstNames, result.Details.Surnames)
+		}
+	}
+
+	// Without the option the input casing is echoed back
+	result := New(createTestDataset()).DetectPII([]string{"jose", "garcia"})
+	if !equalStringSlices(result.Details.FirstNames, []string{"jose"}) {
+		t.Errorf("Expected raw first names, got %q", result.Details.FirstNames)
+	}
+}
+
+func TestLocaleProfile_TitleCase(t *testing.T) {
+	tests := map[string]string{
+		"garcía-lópez": "García-López",
+		"O'BRIEN":      "O'Brien",
+		"McDonald":     "McDonald",
+		"jose\u0301":   "Jose\u0301", // NFD input
+		"ÁLVARO":       "Álvaro",
+	}
+	for input, expected := range tests {
+		if got := (LocaleProfile{}).titleCase(input); got != expected {
+			t.Errorf("titleCase(%q): expected %q, got %q", input, expected, got)
+		}
+	}
+
+	if got := GetLocaleProfile("tr").titleCase("istanbul"); got != "İstanbul" {
+		t.Errorf("Expected Turkish casing to give %q, got %q", "İstanbul", got)
+	}
+}
+
+func TestDetectPII_FullWidthLatin(t *testing.T) {
+	detector := New(createTestDataset())
+
+	fullWidth := detector.DetectPII([]string{"Ｊｏｓｅ", "Ｇａｒｃｉａ"})
+	ascii := detector.DetectPII([]string{"Jose", "Garcia"})
+
+	if fullWidth.Confidence != ascii.Confidence || !fullWidth.IsLikelyName {
+		t.Errorf("Expected full-width input to match like ASCII (%.3f), got %.3f", ascii.Confidence, fullWidth.Confidence)
+	}
+	if !equalStringSlices(fullWidth.Details.FirstNames, []string{"Ｊｏｓｅ"}) {
+		t.Errorf("Expected the original full-width first name, got %q", fullWidth.Details.FirstNames)
+	}
+
+	// Locale profiles narrow full-width input too
+	if got := GetLocaleProfile("tr").normalizeForLookup("Ｊｏｓｅ"); got != "JOSE" {
+		t.Errorf("Expected the Turkish profile to narrow full-width input to JOSE, got %q", got)
+	}
+}
+
+func TestLocaleProfile_TurkishCasing(t *testing.T) {
+	turkish := GetLocaleProfile("tr-TR")
+	def := GetLocaleProfile("")
+
+	upperTests := []struct {
+		input    string
+		turkish  string
+		fallback string
+	}{
+		{"İstanbul", "İSTANBUL", "İSTANBUL"},
+		{"istanbul", "İSTANBUL", "ISTANBUL"}, // Naive casing loses the dot
+		{"ışık", "IŞIK", "IŞIK"},
+	}
+	for _, tt := range upperTests {
+		if got := turkish.toUpper(tt.input); got != tt.turkish {
+			t.Errorf("turkish.toUpper(%q) = %q, want %q", tt.input, got, tt.turkish)
+		}
+		if got := def.toUpper(tt.input); got != tt.fallback {
+			t.Errorf("default.toUpper(%q) = %q, want %q", tt.input, got, tt.fallback)
+		}
+	}
+
+	if got := turkish.toLower("IŞIK"); got != "ışık" {
+		t.Errorf("turkish.toLower(%q) = %q, want %q", "IŞIK", got, "ışık")
+	}
+	if got := turkish.toLower("İSTANBUL"); got != "istanbul" {
+		t.Errorf("turkish.toLower(%q) = %q, want %q", "İSTANBUL", got, "istanbul")
+	}
+
+	lookupTests := []struct {
+		input    string
+		expected string
+	}{
+		{"İstanbul", "ISTANBUL"},
+		{"istanbul", "ISTANBUL"},
+		{"ışık", "ISIK"},
+		{"Işık", "ISIK"},
+	}
+	for _, tt := range lookupTests {
+		if got := turkish.normalizeForLookup(tt.input); got != tt.expected {
+			t.Errorf("turkish.normalizeForLookup(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestLocaleProfile_Validation(t *testing.T) {
+	vietnamese := GetLocaleProfile("vi")
+	if got := vietnamese.normalizeForLookup("Đặng"); got != "DANG" {
+		t.Errorf("vietnamese.normalizeForLookup(%q) = %q, want %q", "Đặng", got, "DANG")
+	}
+	if vietnamese.isValidRune('Ж') {
+		t.Errorf("Expected Cyrillic letters to be rejected by the Latin-only Vietnamese profile")
+	}
+	if !GetLocaleProfile("").isValidRune('Ж') {
+		t.Errorf("Expected the default profile to accept any letter")
+	}
+
+	// The locale hint reaches lookups through ScoreConfig
+	dataset := createTestDataset()
+	dataset.FirstNames["İBRAHİM"] = &types.NameData{
+		Country: map[string]float32{"TR": 0.8},
+		Gender:  map[string]float32{"M": 1.0},
+		Rank:    map[string]int32{"TR": 4},
+	}
+	config := DefaultScoreConfig()
+	config.Locale = "tr"
+	scorer := NewScorer(dataset, config)
+	if _, exists := scorer.lookup("ibrahim", true); !exists {
+		t.Errorf("Expected Turkish casing to find İBRAHİM from lowercase input")
+	}
+	if _, exists := NewScorer(dataset, DefaultScoreConfig()).lookup("ibrahim", true); exists {
+		t.Errorf("Expected default casing not to find İBRAHİM from lowercase input")
+	}
+}
+
+func TestTransliterateToLatin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Иван", "Ivan"},
+		{"Жанна", "Zhanna"},
+		{"ЖАННА", "ZHANNA"},
+		{"Щербаков", "Shcherbakov"},
+		{"Олександр", "Oleksandr"},
+		{"Γιώργος", "Giorgos"},
+		{"Θεόδωρος", "Theodoros"},
+		{"José García", "José García"}, // Latin is left alone
+	}
+
+	for _, tt := range tests {
+		if got := TransliterateToLatin(tt.input); got != tt.expected {
+			t.Errorf("TransliterateToLatin(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestLocaleProfile_Transliteration(t *testing.T) {
+	if got := GetLocaleProfile("ru").normalizeForLookup("Иван"); got != "IVAN" {
+		t.Errorf("russian.normalizeForLookup(%q) = %q, want %q", "Иван", got, "IVAN")
+	}
+	if got := GetLocaleProfile("").normalizeForLookup("Иван"); got != "ИВАН" {
+		t.Errorf("Expected the default profile not to transliterate, got %q", got)
+	}
+
+	dataset := createTestDataset()
+	dataset.FirstNames["GIORGOS"] = &types.NameData{
+		Country: map[string]float32{"GR": 0.9},
+		Gender:  map[string]float32{"M": 1.0},
+		Rank:    map[string]int32{"GR": 3},
+	}
+	config := DefaultScoreConfig()
+	config.Transliterate = true
+	if _, exists := NewScorer(dataset, config).lookup("Γιώργος", true); !exists {
+		t.Errorf("Expected Transliterate to find GIORGOS from Greek input")
+	}
+	if _, exists := NewScorer(dataset, DefaultScoreConfig()).lookup("Γιώργος", true); exists {
+		t.Errorf("Expected Greek input not to match without transliteration")
+	}
+}
+
+func TestScoreConfig_Transliterations(t *testing.T) {
+	dataset := createTestDataset()
+	dataset.FirstNames["SEMYON"] = &types.NameData{
+		Country: map[string]float32{"RU": 0.9},
+		Gender:  map[string]float32{"M": 1.0},
+		Rank:    map[string]int32{"RU": 40},
+	}
+
+	// The built-in table maps "ё" like "е"
+	config := DefaultScoreConfig()
+	config.Transliterate = true
+	if _, exists := NewScorer(dataset, config).lookup("Семён", true); exists {
+		t.Errorf("Expected the built-in table to look up SEMEN, not SEMYON")
+	}
+
+	table := LatinTransliterations()
+	table['ё'] = "yo"
+	config = DefaultScoreConfig()
+	config.Transliterations = table
+	if _, exists := NewScorer(dataset, config).lookup("Семён", true); !exists {
+		t.Errorf("Expected a custom table to find SEMYON")
+	}
+	if _, exists := NewScorer(dataset, config).lookup("Jose", true); !exists {
+		t.Errorf("Expected Latin input to be unaffected by a custom table")
+	}
+	if latinTransliterations['ё'] != "e" {
+		t.Errorf("Expected LatinTransliterations to return a copy")
+	}
+}
+
 // Benchmark the normalization function
 func BenchmarkNormalizeAccents(b *testing.B) {
 	testNames := []string{"José", "García", "François", "Müller", "María García López"}
@@ -70,4 +338,59 @@ func BenchmarkNormalizeAccents(b *testing.B) {
 			normalizeAccents(name)
 		}
 	}
-}
\ No newline at end of file
+}
+
+// Benchmark lookup normalization on a realistic mix of repeated common
+// names, most of them plain ASCII
+func BenchmarkNormalizeForLookup(b *testing.B) {
+	testNames := []string{"John", "Smith", "María", "García", "Jose", "Lopez", "Müller", "Maria", "Garcia", "Williams"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, name := range testNames {
+			normalizeForLookup(name)
+		}
+	}
+}
+
+// Benchmark scoring accented Spanish names with and without the
+// normalization cache
+func BenchmarkScoreCombination_AccentedSpanish(b *testing.B) {
+	combo := types.NameCombination{FirstNames: []string{"José", "María"}, Surnames: []string{"García", "López"}}
+
+	for _, size := range []int{0, 4096} {
+		config := DefaultScoreConfig()
+		config.NormalizationCacheSize = size
+		scorer := NewScorer(createTestDataset(), config)
+
+		b.Run(fmt.Sprintf("cache=%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				scorer.ScoreCombination(combo)
+			}
+		})
+	}
+}
+
+// Benchmark scoring accented Spanish names from concurrent goroutines
+// sharing one scorer, where every cached lookup takes the cache mutex.
+// Compare with -cpu 1,4,8 to see how the cache behaves under contention
+func BenchmarkScoreCombination_AccentedSpanishParallel(b *testing.B) {
+	combo := types.NameCombination{FirstNames: []string{"José", "María"}, Surnames: []string{"García", "López"}}
+
+	for _, size := range []int{0, 4096} {
+		config := DefaultScoreConfig()
+		config.NormalizationCacheSize = size
+		scorer := NewScorer(createTestDataset(), config)
+
+		b.Run(fmt.Sprintf("cache=%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					scorer.ScoreCombination(combo)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package detector
+
+import (
+	"math"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+// listSizes holds the per-country list sizes of one dataset and the longest
+// list of each role, which percentile ranks are rescaled to
+type listSizes struct {
+	dataset        *types.NameDataset
+	firstNames     map[string]int32
+	surnames       map[string]int32
+	longestFirst   int32
+	longestSurname int32
+}
+
+// listSizesFor returns the list sizes of dataset, taking those the loader
+// filled in or computing them on first use and again after the dataset is
+// replaced
+func (s *Scorer) listSizesFor(dataset *types.NameDataset) *listSizes {
+	if sizes := s.sizes.Load(); sizes != nil && sizes.dataset == dataset {
+		return sizes
+	}
+
+	s.sizesMu.Lock()
+	defer s.sizesMu.Unlock()
+	if sizes := s.sizes.Load(); sizes != nil && sizes.dataset == dataset {
+		return sizes
+	}
+
+	sizes := &listSizes{
+		dataset:    dataset,
+		firstNames: dataset.FirstNameListSizes,
+		surnames:   dataset.LastNameListSizes,
+	}
+	if sizes.firstNames == nil {
+		sizes.firstNames = types.ListSizes(dataset.FirstNames)
+	}
+	if sizes.surnames == nil {
+		sizes.surnames = types.ListSizes(dataset.LastNames)
+	}
+	sizes.longestFirst = longestList(sizes.firstNames)
+	sizes.longestSurname = longestList(sizes.surnames)
+	s.sizes.Store(sizes)
+	return sizes
+}
+
+// longestList returns the largest of sizes
+func longestList(sizes map[string]int32) int32 {
+	var longest int32
+	for _, size := range sizes {
+		longest = max(longest, size)
+	}
+	return longest
+}
+
+// percentileRank returns the best rank of nameData across countries after
+// rescaling each to the longest list of its role: rank 10 of 500 becomes
+// rank 400 next to a list of 20,000. It returns 0 when the name has no rank.
+func (s *Scorer) percentileRank(nameData *types.NameData, isFirstName bool) int32 {
+	sizes := s.listSizesFor(s.dataset.Load())
+	countrySizes, longest := sizes.surnames, sizes.longestSurname
+	if isFirstName {
+		countrySizes, longest = sizes.firstNames, sizes.longestFirst
+	}
+
+	var best int32
+	for country, rank := range nameData.Rank {
+		if rank <= 0 {
+			continue
+		}
+		scaled := rank
+		if size := countrySizes[country]; size > 0 {
+			// Names added after the sizes were taken may rank past the end
+			percentile := float64(min(rank, size)) / float64(size)
+			scaled = int32(math.Ceil(percentile * float64(longest)))
+		}
+		if best == 0 || scaled < best {
+			best = scaled
+		}
+	}
+	return best
+}
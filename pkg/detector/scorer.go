@@ -1,12 +1,31 @@
 package detector
 
 import (
+	"fmt"
 	"math"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
 
 	"github.com/montevive/go-name-detector/pkg/types"
 )
 
+// AveragingMode selects the denominator used to average per-name scores
+type AveragingMode int
+
+const (
+	// AverageAllTokens divides by every token in the combination, so tokens
+	// missing from the dataset dilute the average
+	AverageAllTokens AveragingMode = iota
+
+	// AverageMatchedTokens divides by the tokens found in the dataset only,
+	// so "2 matched of 2" and "2 matched of 4" average the same. The
+	// multiple-names bonus then also counts matched tokens only.
+	AverageMatchedTokens
+)
+
 // ScoreConfig holds configuration for the scoring algorithm
 type ScoreConfig struct {
 	BaseMatchScore     float64 // Base score for finding a name in database
@@ -14,6 +33,106 @@ type ScoreConfig struct {
 	GenderConsistency  float64 // Bonus for consistent gender across first names
 	CountryOverlap     float64 // Bonus for country overlap between components
 	MultipleNamesBonus float64 // Bonus for finding multiple valid names
+	RoleFitBonus       float64 // Bonus scaled by how well tokens fit their assigned role; 0 (the default) disables it
+	HintBonus          float64 // Bonus scaled by agreement with a country or gender hint
+	HintPenalty        float64 // Penalty scaled by disagreement with a country or gender hint
+
+	// Averaging selects whether unmatched tokens count towards the average
+	Averaging AveragingMode
+
+	// UnknownTokenWeight is the score credited to a token missing from the
+	// dataset that is still name-shaped (capitalized, mixed case, not a
+	// preposition), as long as another token in the combination is a known
+	// name. It trades precision for recall on names the dataset doesn't
+	// cover, such as "José Unknownsurname". Zero (the default) disables it;
+	// it has no effect with AverageMatchedTokens, which already ignores
+	// unknown tokens.
+	UnknownTokenWeight float64
+
+	// MissingCountryDiscount is subtracted from the score, scaled by the
+	// fraction of matched names whose entries have no country data, so names
+	// that can only be judged on rank score a little lower. Zero (the
+	// default) just leaves country-less entries out of country scoring.
+	MissingCountryDiscount float64
+
+	// TopPairTiers multiply the score when the strongest first name and
+	// strongest surname are both ranked within a tier's MaxRank. A two-token
+	// name takes each token's best rank, as a first name when it is one; with
+	// more tokens on either side, every token must be a known name for its
+	// role and ranks are taken from that role. Only the strictest matching
+	// tier applies, so a top-10 tier can be added alongside the default
+	// top-100 one.
+	TopPairTiers []TopPairTier
+
+	// Calibration maps the final score through a logistic curve so that
+	// Confidence reads as the probability that the input is a name, which
+	// the raw score is not. It is off by default; DefaultCalibration holds
+	// fitted parameters. Thresholds are then probabilities, so 0.5 means
+	// even odds.
+	Calibration Calibration
+
+	// Fuzzy matches tokens missing from the dataset against common names
+	// within a small edit distance, so typos such as "Jhon" or "Gracia"
+	// still count, at a discount. It is off by default, since each unknown
+	// token then costs an index search; DefaultFuzzyMatch enables it.
+	Fuzzy FuzzyMatch
+
+	// PercentileRanks scores popularity by where a name ranks within each
+	// country's list rather than by its absolute rank, so rank 50 of 500
+	// names counts for less than rank 50 of 19,000. Ranks are rescaled to
+	// the longest list of their role before the popularity tiers apply,
+	// which leaves that list's ranks unchanged. Off by default.
+	PercentileRanks bool
+
+	// NoiseFloor caps the score of combinations whose matched names all rank
+	// beyond its MinRank, so pairs of very rare dataset entries can't stack
+	// bonuses past the threshold. A zero MinRank disables it.
+	NoiseFloor NoiseFloor
+
+	// Locale selects a validation and casing profile ("tr", "az", "vi"); empty
+	// uses the default Unicode rules. Turkish and Azeri need their own dotted
+	// and dotless "i" casing for lookups to be correct, and the Cyrillic and
+	// Greek locales ("ru", "uk", "bg", "el", ...) transliterate to Latin.
+	Locale string
+
+	// Transliterate converts Cyrillic and Greek input to Latin before lookup
+	// for any locale, so "Иван" matches "IVAN" in a Latin-script dataset
+	Transliterate bool
+
+	// Transliterations replaces the built-in table, keyed by lowercase
+	// letter, and turns transliteration on for any locale. Start from
+	// LatinTransliterations to change a few ambiguous letters, such as "ё"
+	// to "yo" for a dataset that spells "Семён" as "SEMYON".
+	Transliterations map[rune]string
+
+	// Prepositions are lowercase connectors ("de", "van") that are penalized
+	// when used as a first name or dangling surname and never count as
+	// name-shaped. See PrepositionsFor to select them by language.
+	Prepositions map[string]bool
+
+	// DiscountSurnameParticles leaves prepositions that lead into a known
+	// surname ("van" in "van Dijk", "de la" in "de la Cruz") out of the token
+	// count when averaging, so a compound surname isn't diluted by its
+	// particles. Off by default, which counts them as unknown tokens and
+	// scores such names lower.
+	DiscountSurnameParticles bool
+
+	// UnisexMargin is the largest difference between the male and female
+	// shares of a name's gender data for it to be predicted "Unisex" rather
+	// than the leading gender, so a 52/48 "Alex" isn't reported as Male
+	UnisexMargin float64
+
+	// StopWords are lowercase common words ("the", "with") dropped from the
+	// input before scoring, since they can't be part of a name
+	StopWords map[string]bool
+
+	// NormalizationCacheSize bounds an LRU cache of the normalized lookup
+	// keys of non-ASCII names, so accented names scored repeatedly, as in
+	// every split of the same input, skip the Unicode transforms. Plain ASCII
+	// names are cheap to normalize and never cached. The cache sits behind a
+	// single mutex, so it can contend when one scorer is shared by many
+	// goroutines. Zero, the default, disables the cache.
+	NormalizationCacheSize int
 }
 
 // DefaultScoreConfig returns the default scoring configuration
@@ -24,41 +143,296 @@ func DefaultScoreConfig() ScoreConfig {
 		GenderConsistency:  0.1,  // Keep same
 		CountryOverlap:     0.15, // Slightly lower (was 0.2) - make room for popularity
 		MultipleNamesBonus: 0.15, // Keep same
+		RoleFitBonus:       0,    // Opt-in reward for role-appropriate tokens
+		HintBonus:          0.1,  // Matching external evidence is a strong signal
+		HintPenalty:        0.05, // Contradictions only slightly lower confidence
+		Averaging:          AverageAllTokens, // Unmatched tokens count as zero
+		TopPairTiers: []TopPairTier{
+			{MaxRank: 100, Multiplier: 1.4}, // Significant boost for common name pairs
+		},
+		NoiseFloor:         NoiseFloor{MinRank: rareRankThreshold, MaxScore: 0.3}, // Rare-only names stay well below 0.7
+		UnisexMargin:       0.1, // 55/45 or closer is Unisex
+		Prepositions: DefaultPrepositions(),
+		StopWords:    DefaultStopWords(),
+
+		NormalizationCacheSize: 0, // Off: the pooled transforms are cheap enough for most inputs
+	}
+}
+
+// languagePrepositions holds the name connectors of each language, keyed by
+// ISO 639-1 code
+var languagePrepositions = map[string][]string{
+	"es": {"de", "del", "la", "el", "los", "las", "y"},
+	"pt": {"de", "da", "do", "dos", "das"},
+	"fr": {"de", "du", "le", "la", "les"},
+	"it": {"di", "da", "del", "della", "degli", "dei"},
+	"nl": {"van", "der", "den", "de", "ter", "ten"},
+	"de": {"von", "zu", "der", "den"},
+	"en": {"of", "and"},
+	"ar": {"bin", "ibn", "bint"},
+	"sv": {"af", "av"},
+	"da": {"af", "av"},
+	"no": {"af", "av"},
+}
+
+// defaultPrepositionLanguages are the languages whose connectors make up
+// DefaultPrepositions. Arabic "bin" and Scandinavian "af" are left out, as
+// the dataset also knows them as names ("Bin", "Lars af" splits) and they
+// would be penalized as first names.
+var defaultPrepositionLanguages = []string{"es", "pt", "fr", "nl", "de", "en"}
+
+// DefaultPrepositions returns the default Spanish, Portuguese, French,
+// Dutch/German and English name connectors
+func DefaultPrepositions() map[string]bool {
+	return PrepositionsFor(defaultPrepositionLanguages...)
+}
+
+// PrepositionsFor returns the name connectors of the given languages
+// ("es", "pt", "fr", "it", "nl", "de", "en", "ar", "sv", "da", "no"), for
+// ScoreConfig.Prepositions. A Spanish-only pipeline can use
+// PrepositionsFor("es") so Dutch "van" or French "le" aren't penalized.
+// Unknown languages are ignored.
+func PrepositionsFor(languages ...string) map[string]bool {
+	prepositions := make(map[string]bool)
+	for _, language := range languages {
+		for _, word := range languagePrepositions[strings.ToLower(language)] {
+			prepositions[word] = true
+		}
+	}
+	return prepositions
+}
+
+// DefaultStopWords returns the default English words that are never part of
+// a name
+func DefaultStopWords() map[string]bool {
+	return map[string]bool{
+		"the": true, "and": true, "or": true, "but": true, "in": true, "on": true,
+		"at": true, "to": true, "for": true, "of": true, "with": true, "by": true,
+		"is": true, "are": true, "was": true, "were": true, "be": true, "been": true,
+		"have": true, "has": true, "had": true, "do": true, "does": true, "did": true,
+		"will": true, "would": true, "could": true, "should": true, "may": true, "might": true,
+		"can": true, "must": true, "shall": true, "this": true, "that": true, "these": true,
+		"those": true, "a": true, "an": true, "it": true, "he": true, "she": true,
+		"they": true, "we": true, "you": true, "i": true, "me": true, "him": true,
+		"her": true, "them": true, "us": true, "my": true, "your": true, "his": true,
+		"our": true, "their": true, "its": true,
+	}
+}
+
+// languageStopWords holds common words per language that are never part of
+// a name. Articles and particles used inside surnames ("de la", "von der")
+// are left out, since they are handled as Prepositions instead, as are short
+// words that double as given names elsewhere ("Mi", "Son").
+var languageStopWords = map[string][]string{
+	"es": {
+		"que", "en", "con", "por", "para", "un", "una", "unos", "unas", "es",
+		"está", "están", "pero", "como", "su", "sus", "se", "lo", "al", "muy",
+		"sin", "sobre", "este", "esta", "esto", "ese", "esa", "hay", "fue",
+		"ser", "tiene", "le", "les", "nos", "también",
+	},
+	"pt": {
+		"que", "em", "com", "por", "para", "um", "uma", "é", "são", "está",
+		"mas", "como", "seu", "sua", "se", "ao", "no", "na", "nos", "nas",
+		"muito", "sem", "sobre", "este", "esta", "isso", "foi", "ser", "tem",
+	},
+	"de": {
+		"und", "die", "das", "ist", "nicht", "mit", "ein", "eine", "einen",
+		"auf", "für", "sich", "auch", "dem", "des", "im", "es", "wir", "sie",
+		"ich", "er", "aber", "oder", "wie", "bei", "nach", "aus", "wird", "sind",
+	},
+	"fr": {
+		"et", "est", "un", "une", "des", "en", "que", "qui", "dans", "pour",
+		"pas", "sur", "avec", "ce", "cette", "il", "elle", "nous", "vous", "ils",
+		"mais", "ou", "au", "aux", "par", "sont", "ses",
+	},
+}
+
+// StopWordsFor returns the default English stop words plus the common words
+// of the given languages ("es", "pt", "de", "fr"), for ScoreConfig.StopWords.
+// Unknown languages are ignored.
+func StopWordsFor(languages ...string) map[string]bool {
+	stopWords := DefaultStopWords()
+	for _, language := range languages {
+		for _, word := range languageStopWords[strings.ToLower(language)] {
+			stopWords[word] = true
+		}
+	}
+	return stopWords
+}
+
+// WithStopWords returns a copy of c whose StopWords also contain words,
+// matched case-insensitively. c's own map is left unchanged; to replace the
+// list instead, assign StopWords directly.
+func (c ScoreConfig) WithStopWords(words map[string]bool) ScoreConfig {
+	merged := make(map[string]bool, len(c.StopWords)+len(words))
+	for word, stop := range c.StopWords {
+		merged[word] = stop
+	}
+	for word, stop := range words {
+		merged[strings.ToLower(word)] = stop
 	}
+	c.StopWords = merged
+	return c
+}
+
+// TopPairTier is a popularity tier for the strong-pair boost
+type TopPairTier struct {
+	MaxRank    int32   // Both strongest names must have a rank at or below this
+	Multiplier float64 // Score multiplier applied when the tier matches
+}
+
+// Calibration is a logistic mapping from raw scores to probabilities:
+// 1 / (1 + e^(-Slope * (score - Midpoint)))
+type Calibration struct {
+	Slope    float64 // Steepness of the curve; zero disables calibration
+	Midpoint float64 // Raw score that maps to a probability of 0.5
+}
+
+// DefaultCalibration returns parameters fitted by logistic regression to the
+// default scoring of 50 hand-labelled names and 50 non-name phrases
+// (business terms, greetings, UI labels) against the embedded dataset
+func DefaultCalibration() Calibration {
+	return Calibration{Slope: 17.5, Midpoint: 0.51}
 }
 
+// Apply maps a score in [0, 1] to a calibrated probability. The mapping is
+// strictly increasing, so it never changes which of two scores is higher. A
+// score of zero, meaning nothing matched, stays zero, and score is returned
+// unchanged when Slope is zero.
+func (c Calibration) Apply(score float64) float64 {
+	if c.Slope == 0 || score <= 0 {
+		return score
+	}
+	return 1 / (1 + math.Exp(-c.Slope*(score-c.Midpoint)))
+}
+
+// NoiseFloor is the score ceiling for combinations made only of rare names
+type NoiseFloor struct {
+	MinRank  int32   // Every matched name must have a rank beyond this
+	MaxScore float64 // Ceiling applied to the score
+}
+
+// rareRankThreshold is the rank beyond which a name falls in the lowest
+// popularity tier of calculatePopularityScore
+const rareRankThreshold = 1000
+
 // Scorer handles confidence scoring for name combinations
 type Scorer struct {
 	config  ScoreConfig
-	dataset *types.NameDataset
+	dataset atomic.Pointer[types.NameDataset] // Swapped by SetDataset
+	profile LocaleProfile
+	keys    *keyCache // Normalized lookup keys, nil when disabled
+
+	fuzzy   atomic.Pointer[fuzzyIndex] // Built on the first fuzzy lookup
+	fuzzyMu sync.Mutex                 // Serializes index builds
+
+	sizes   atomic.Pointer[listSizes] // Built on the first percentile rank
+	sizesMu sync.Mutex                // Serializes list size builds
 }
 
 // NewScorer creates a new scorer with the given dataset and config
 func NewScorer(dataset *types.NameDataset, config ScoreConfig) *Scorer {
-	return &Scorer{
+	profile := GetLocaleProfile(config.Locale)
+	switch {
+	case config.Transliterations != nil:
+		profile.Transliteration = config.Transliterations
+	case config.Transliterate && profile.Transliteration == nil:
+		profile.Transliteration = latinTransliterations
+	}
+
+	s := &Scorer{
 		config:  config,
-		dataset: dataset,
+		profile: profile,
+	}
+	if config.NormalizationCacheSize > 0 {
+		s.keys = newKeyCache(config.NormalizationCacheSize)
 	}
+	s.dataset.Store(dataset)
+	return s
+}
+
+// SetDataset replaces the dataset used for lookups. It is safe to call while
+// other goroutines are scoring: each lookup reads either the old or the new
+// dataset in full.
+func (s *Scorer) SetDataset(dataset *types.NameDataset) {
+	s.dataset.Store(dataset)
 }
 
-// ScoreCombination calculates a confidence score for a name combination
+// ScoreCombination calculates a confidence score for a name combination,
+// calibrated when ScoreConfig.Calibration is set
 func (s *Scorer) ScoreCombination(combo types.NameCombination) float64 {
-	if len(combo.FirstNames) == 0 || len(combo.Surnames) == 0 {
+	return s.config.Calibration.Apply(s.clampedScore(combo))
+}
+
+// clampedScore calculates the uncalibrated score of a combination, capped at
+// 1.0. Combinations are ranked by it, so those boosted past 1.0 tie.
+func (s *Scorer) clampedScore(combo types.NameCombination) float64 {
+	return math.Min(1.0, s.rawScore(combo))
+}
+
+// rawScore calculates the score of a combination before clamping, which its
+// factors add up to
+func (s *Scorer) rawScore(combo types.NameCombination) float64 {
+	return s.scoreWithFactors(combo, nil)
+}
+
+// Factors returns the signed contributions that make up a combination's
+// unclamped score, in scoring order
+func (s *Scorer) Factors(combo types.NameCombination) []types.Factor {
+	var factors []types.Factor
+	s.scoreWithFactors(combo, &factors)
+	return factors
+}
+
+// scoreWithFactors calculates the unclamped score of a combination. When
+// factors is non-nil, each contribution to the score is appended to it.
+func (s *Scorer) scoreWithFactors(combo types.NameCombination, factors *[]types.Factor) float64 {
+	// Both roles must be filled, except by a single-word mononym
+	tokenCount := len(combo.FirstNames) + len(combo.Surnames)
+	if tokenCount == 0 || (tokenCount > 1 && (len(combo.FirstNames) == 0 || len(combo.Surnames) == 0)) {
 		return 0.0
 	}
 
+	record := func(name string, impact float64, detail string) {
+		if factors != nil && impact != 0 {
+			*factors = append(*factors, types.Factor{Name: name, Impact: impact, Detail: detail})
+		}
+	}
+
+	if tokenCount == 1 {
+		return s.mononymScore(combo, record)
+	}
+
 	var totalScore float64
-	var componentCount int
 
 	// Score first names
 	firstNamesScore, firstNamesData := s.scoreNames(combo.FirstNames, true)
 	totalScore += firstNamesScore
-	componentCount += len(combo.FirstNames)
 
 	// Score surnames
 	surnamesScore, surnamesData := s.scoreNames(combo.Surnames, false)
 	totalScore += surnamesScore
-	componentCount += len(combo.Surnames)
+
+	matchedCount := len(firstNamesData) + len(surnamesData)
+	matchedScore := totalScore
+
+	// Credit name-shaped tokens the dataset doesn't know when others match
+	var credited int
+	if s.config.UnknownTokenWeight > 0 && s.config.Averaging == AverageAllTokens && matchedCount > 0 {
+		credited = s.countNameShapedUnknown(combo)
+		totalScore += s.config.UnknownTokenWeight * float64(credited)
+	}
+
+	// Count either every token, optionally but surname particles, or only
+	// the matched ones
+	countedTokens := tokenCount
+	if s.config.DiscountSurnameParticles {
+		countedTokens -= s.countSurnameParticles(combo.Surnames)
+	}
+	componentCount := countedTokens
+	if s.config.Averaging == AverageMatchedTokens {
+		componentCount = matchedCount
+	}
 
 	if componentCount == 0 {
 		return 0.0
@@ -67,32 +441,88 @@ func (s *Scorer) ScoreCombination(combo types.NameCombination) float64 {
 	// Average base score
 	averageScore := totalScore / float64(componentCount)
 
+	if factors != nil && matchedCount > 0 {
+		matchedAverage := matchedScore / float64(matchedCount)
+		record("name_matches", matchedAverage,
+			fmt.Sprintf("%d of %d tokens matched as names", matchedCount, tokenCount))
+		record("unknown_tokens", matchedScore/float64(componentCount)-matchedAverage,
+			countNoun(countedTokens-matchedCount, "token")+" not found in their role")
+		record("unknown_credit", s.config.UnknownTokenWeight*float64(credited)/float64(componentCount),
+			countNoun(credited, "name-shaped unknown token")+" credited")
+	}
+
 	// Add bonus for gender consistency among first names
 	if len(firstNamesData) > 1 {
 		genderBonus := s.calculateGenderConsistency(firstNamesData)
 		averageScore += genderBonus
+		record("gender_consistency", genderBonus, "first names agree on gender")
 	}
 
 	// Add bonus for country overlap between first names and surnames
 	if len(firstNamesData) > 0 && len(surnamesData) > 0 {
 		countryBonus := s.calculateCountryOverlap(firstNamesData, surnamesData)
 		averageScore += countryBonus
+		if factors != nil {
+			record("country_overlap", countryBonus,
+				fmt.Sprintf("first names and surnames agree on country (%s)", s.GetTopCountry(combo)))
+		}
+	}
+
+	// Discount names whose entries carry no country data
+	if s.config.MissingCountryDiscount > 0 && matchedCount > 0 {
+		missing := countMissingCountry(firstNamesData) + countMissingCountry(surnamesData)
+		discount := s.config.MissingCountryDiscount * float64(missing) / float64(matchedCount)
+		averageScore -= discount
+		if factors != nil {
+			record("missing_country", -discount, countNoun(missing, "name")+" without country data")
+		}
+	}
+
+	// Add bonus for tokens that rank better in their assigned role
+	if matchedCount > 0 && s.config.RoleFitBonus > 0 {
+		roleBonus := s.config.RoleFitBonus * s.RoleFit(combo)
+		averageScore += roleBonus
+		record("role_fit", roleBonus, "tokens rank best in their assigned role")
 	}
 
 	// Add bonus for multiple valid names
 	if componentCount > 2 {
 		averageScore += s.config.MultipleNamesBonus
+		record("multiple_names", s.config.MultipleNamesBonus,
+			fmt.Sprintf("%d-part name", componentCount))
 	}
 
 	// Apply pattern-specific adjustments
-	averageScore = s.applyPatternAdjustments(combo, averageScore)
+	return s.applyPatternAdjustments(combo, averageScore, record)
+}
 
-	// Clamp to [0, 1]
-	if averageScore > 1.0 {
-		averageScore = 1.0
+// mononymScore scores a single-word combination by the popularity of its
+// rank in its role, since there is no second name to corroborate it
+func (s *Scorer) mononymScore(combo types.NameCombination, record func(name string, impact float64, detail string)) float64 {
+	name, isFirstName, role := "", true, "first name"
+	if len(combo.FirstNames) == 1 {
+		name = combo.FirstNames[0]
+	} else {
+		name, isFirstName, role = combo.Surnames[0], false, "surname"
 	}
 
-	return averageScore
+	nameData, method, distance := s.lookupMatch(name, isFirstName)
+	if method == "" {
+		return 0.0
+	}
+
+	score := s.calculatePopularityScore(nameData, isFirstName) * s.config.Fuzzy.weight(distance)
+	record("mononym_rank", score,
+		fmt.Sprintf("%q ranks %d as a %s", name, s.getMinRankFromData(nameData), role))
+	return score
+}
+
+// countNoun formats a count with a singular or plural noun ("1 token", "2 tokens")
+func countNoun(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
 }
 
 // scoreNames scores a list of names (either first names or surnames)
@@ -100,25 +530,9 @@ func (s *Scorer) scoreNames(names []string, isFirstNames bool) (float64, []*type
 	var totalScore float64
 	var nameDataList []*types.NameData
 
-	targetMap := s.dataset.LastNames
-	if isFirstNames {
-		targetMap = s.dataset.FirstNames
-	}
-
 	for _, name := range names {
-		// Try dual lookup: first exact case match, then normalized
-		exactKey := strings.ToUpper(strings.TrimSpace(name))
-		nameData, exists := targetMap[exactKey]
-
-		if !exists {
-			// Try with accent normalization
-			normalizedKey := normalizeForLookup(name)
-			if normalizedKey != exactKey {
-				nameData, exists = targetMap[normalizedKey]
-			}
-		}
-
-		if !exists {
+		nameData, method, distance := s.lookupMatch(name, isFirstNames)
+		if method == "" {
 			// Name not found in database
 			continue
 		}
@@ -129,30 +543,194 @@ func (s *Scorer) scoreNames(names []string, isFirstNames bool) (float64, []*type
 		score := s.config.BaseMatchScore
 
 		// Add popularity bonus (lower rank = higher score)
-		popularityScore := s.calculatePopularityScore(nameData)
+		popularityScore := s.calculatePopularityScore(nameData, isFirstNames)
 		score += popularityScore * s.config.PopularityWeight
 
-		totalScore += score
+		// Misspelled names only earn part of the score of their match
+		totalScore += score * s.config.Fuzzy.weight(distance)
 	}
 
 	return totalScore, nameDataList
 }
 
-// calculatePopularityScore calculates score based on name popularity
-func (s *Scorer) calculatePopularityScore(nameData *types.NameData) float64 {
-	if len(nameData.Rank) == 0 {
-		return 0.0
+// countNameShapedUnknown counts the tokens missing from the map of their role
+// that look like names
+func (s *Scorer) countNameShapedUnknown(combo types.NameCombination) int {
+	count := 0
+	for _, side := range []struct {
+		names       []string
+		isFirstName bool
+	}{{combo.FirstNames, true}, {combo.Surnames, false}} {
+		for _, name := range side.names {
+			if _, exists := s.lookup(name, side.isFirstName); !exists && s.isNameShaped(name) {
+				count++
+			}
+		}
 	}
+	return count
+}
 
-	// Find the best (lowest) rank across all countries
-	minRank := int32(math.MaxInt32)
-	for _, rank := range nameData.Rank {
-		if rank > 0 && rank < minRank {
-			minRank = rank
+// isNameShaped reports whether a token is capitalized like a name: an upper
+// or title case first letter followed by at least one lowercase letter, so
+// lowercase words and all-caps acronyms don't qualify. Every other character
+// must be valid in a name word, which rules out codes such as "Room101".
+func (s *Scorer) isNameShaped(word string) bool {
+	if s.isProbablyPreposition(word) {
+		return false
+	}
+
+	hasLower := false
+	for i, r := range word {
+		if i == 0 {
+			if !unicode.IsUpper(r) && !unicode.IsTitle(r) {
+				return false
+			}
+			continue
+		}
+		if !s.profile.isValidRune(r) {
+			return false
+		}
+		if unicode.IsLower(r) {
+			hasLower = true
+		}
+	}
+	return hasLower
+}
+
+// Lookup methods reported in NameMatchDetail.Lookup
+const (
+	lookupExact      = "exact"
+	lookupNormalized = "normalized"
+	lookupCompact    = "punctuation_stripped"
+	lookupCompound   = "compound"
+	lookupFuzzy      = "fuzzy"
+)
+
+// lookup finds a name in the first or last name map using dual lookup:
+// first the exact case-folded key, then the accent-normalized key. A token
+// with periods, apostrophes or hyphens is also looked up without them, so
+// "St. John", "O'Brien" and "Jean-Pierre" match the dataset's "ST JOHN",
+// "OBRIEN" and "JEANPIERRE", and as a compound through its parts (see
+// lookupCompoundParts). Of the variants found, the best-ranked one is used.
+// With ScoreConfig.Fuzzy enabled, a name found none of these ways falls back
+// to the closest common name within the configured edit distance.
+func (s *Scorer) lookup(name string, isFirstName bool) (*types.NameData, bool) {
+	nameData, method := s.lookupWithMethod(name, isFirstName)
+	return nameData, method != ""
+}
+
+// lookupWithMethod is lookup that also reports which key matched, or an
+// empty method when the name isn't found
+func (s *Scorer) lookupWithMethod(name string, isFirstName bool) (*types.NameData, string) {
+	nameData, method, _ := s.lookupMatch(name, isFirstName)
+	return nameData, method
+}
+
+// lookupMatch is lookupWithMethod that also reports the edit distance of a
+// fuzzy match, which is zero for every other method
+func (s *Scorer) lookupMatch(name string, isFirstName bool) (*types.NameData, string, int) {
+	dataset := s.dataset.Load()
+	targetMap := dataset.LastNames
+	if isFirstName {
+		targetMap = dataset.FirstNames
+	}
+
+	var best *types.NameData
+	var bestMethod string
+
+	exactKey := s.profile.toUpper(strings.TrimSpace(name))
+	normalizedKey := s.normalizedKey(name)
+	if nameData, exists := targetMap[exactKey]; exists {
+		best, bestMethod = nameData, lookupExact
+	} else if nameData, exists := targetMap[normalizedKey]; exists && normalizedKey != exactKey {
+		best, bestMethod = nameData, lookupNormalized
+	}
+
+	if !strings.ContainsAny(normalizedKey, ".'’-") {
+		if best == nil && s.config.Fuzzy.MaxDistance > 0 {
+			return s.fuzzyLookup(dataset, normalizedKey, isFirstName)
+		}
+		return best, bestMethod, 0
+	}
+
+	// Datasets store punctuated names inconsistently, with or without their
+	// punctuation or only as separate parts, so every variant is tried and
+	// the best-ranked one kept. On a tie the earlier variant wins.
+	consider := func(nameData *types.NameData, method string) {
+		if best == nil || s.getMinRankFromData(nameData) < s.getMinRankFromData(best) {
+			best, bestMethod = nameData, method
+		}
+	}
+
+	compactKey := stripNamePunctuation(normalizedKey)
+	if compactKey != normalizedKey {
+		if nameData, exists := targetMap[compactKey]; exists {
+			consider(nameData, lookupCompact)
+		}
+	}
+	if joinedKey := strings.ReplaceAll(compactKey, "-", ""); joinedKey != compactKey {
+		if nameData, exists := targetMap[joinedKey]; exists {
+			consider(nameData, lookupCompact)
+		}
+	}
+
+	if nameData, exists := s.lookupCompoundParts(normalizedKey, targetMap); exists {
+		consider(nameData, lookupCompound)
+	}
+
+	if best == nil && s.config.Fuzzy.MaxDistance > 0 {
+		return s.fuzzyLookup(dataset, normalizedKey, isFirstName)
+	}
+	return best, bestMethod, 0
+}
+
+// normalizedKey returns the profile's lookup key for name, from the
+// normalization cache when enabled and name isn't plain ASCII
+func (s *Scorer) normalizedKey(name string) string {
+	if s.keys == nil || isASCII(name) {
+		return s.profile.normalizeForLookup(name)
+	}
+
+	if key, exists := s.keys.get(name); exists {
+		return key
+	}
+	key := s.profile.normalizeForLookup(name)
+	s.keys.put(name, key)
+	return key
+}
+
+// lookupCompoundParts matches a hyphenated or apostrophe compound when every
+// one of its parts is in targetMap, so "Anne-Marie" is found through ANNE and
+// MARIE among first names and "Lloyd-Webber" through
+// LLOYD and WEBBER among surnames. The token is credited once, with the data
+// of its rarest part, rather than once per part.
+func (s *Scorer) lookupCompoundParts(key string, targetMap map[string]*types.NameData) (*types.NameData, bool) {
+	parts := splitCompoundName(key)
+	if len(parts) == 0 {
+		return nil, false
+	}
+
+	var rarest *types.NameData
+	for _, part := range parts {
+		nameData, exists := targetMap[part]
+		if !exists {
+			return nil, false
+		}
+		if rarest == nil || s.getMinRankFromData(nameData) > s.getMinRankFromData(rarest) {
+			rarest = nameData
 		}
 	}
+	return rarest, true
+}
 
-	if minRank == int32(math.MaxInt32) {
+// calculatePopularityScore calculates score based on name popularity
+func (s *Scorer) calculatePopularityScore(nameData *types.NameData, isFirstName bool) float64 {
+	// Find the best (lowest) rank across all countries
+	minRank := minRankOf(nameData)
+	if s.config.PercentileRanks {
+		minRank = s.percentileRank(nameData, isFirstName)
+	}
+	if minRank == 0 {
 		return 0.0
 	}
 
@@ -164,7 +742,7 @@ func (s *Scorer) calculatePopularityScore(nameData *types.NameData) float64 {
 		return 0.8  // Very common names
 	case minRank <= 200:
 		return 0.5  // Common names
-	case minRank <= 1000:
+	case minRank <= rareRankThreshold:
 		return 0.2  // Uncommon but legitimate names
 	default:
 		return 0.02 // Very rare names (likely noise, typos, or unusual entries)
@@ -243,24 +821,92 @@ func (s *Scorer) calculateCountryOverlap(firstNamesData, surnamesData []*types.N
 	return s.config.CountryOverlap * overlapScore
 }
 
-// GetTopCountry returns the most likely country for a name combination
+// GetTopCountry returns the most likely country for a name combination. When
+// countries tie, the alphabetically first country code wins.
 func (s *Scorer) GetTopCountry(combo types.NameCombination) string {
+	countryScores := s.countryScores(combo)
+
+	// Find the country with highest score, breaking ties on the country code
+	// since map iteration order is random
+	var topCountry string
+	var maxScore float64
+	for country, score := range countryScores {
+		if score > maxScore || (score == maxScore && score > 0 && country < topCountry) {
+			maxScore = score
+			topCountry = country
+		}
+	}
+
+	return topCountry
+}
+
+// CountryDistribution returns the country probabilities of the matched names
+// in a combination, summed per country and normalized to add up to 1.0. It
+// returns nil when no matched name has country data. Map order is random, so
+// callers that display it should sort by probability.
+func (s *Scorer) CountryDistribution(combo types.NameCombination) map[string]float64 {
+	countryScores := s.countryScores(combo)
+
+	var total float64
+	for _, score := range countryScores {
+		total += score
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	for country := range countryScores {
+		countryScores[country] /= total
+	}
+	return countryScores
+}
+
+// GetTopCountries returns the n most likely countries of origin of a
+// combination with their CountryDistribution share, most likely first. Ties
+// are ordered by country code so results are stable across runs. n <= 0
+// returns every country; nil is returned when no matched name has country
+// data.
+func (s *Scorer) GetTopCountries(combo types.NameCombination, n int) []types.CountryScore {
+	distribution := s.CountryDistribution(combo)
+	if len(distribution) == 0 {
+		return nil
+	}
+
+	countries := make([]types.CountryScore, 0, len(distribution))
+	for country, score := range distribution {
+		countries = append(countries, types.CountryScore{Country: country, Score: score})
+	}
+	sort.Slice(countries, func(i, j int) bool {
+		if countries[i].Score != countries[j].Score {
+			return countries[i].Score > countries[j].Score
+		}
+		return countries[i].Country < countries[j].Country
+	})
+
+	if n > 0 && len(countries) > n {
+		countries = countries[:n]
+	}
+	return countries
+}
+
+// countryScores sums the country probabilities of the matched first names
+// and surnames of a combination
+func (s *Scorer) countryScores(combo types.NameCombination) map[string]float64 {
 	countryScores := make(map[string]float64)
 
 	// Add scores from first names
 	for _, name := range combo.FirstNames {
-		// Try dual lookup: first exact case match, then normalized
-		exactKey := strings.ToUpper(strings.TrimSpace(name))
-		nameData, exists := s.dataset.FirstNames[exactKey]
-
-		if !exists {
-			// Try with accent normalization
-			normalizedKey := normalizeForLookup(name)
-			if normalizedKey != exactKey {
-				nameData, exists = s.dataset.FirstNames[normalizedKey]
+		nameData, exists := s.lookup(name, true)
+		if exists {
+			for country, prob := range nameData.Country {
+				countryScores[country] += float64(prob)
 			}
 		}
+	}
 
+	// Add scores from surnames
+	for _, name := range combo.Surnames {
+		nameData, exists := s.lookup(name, false)
 		if exists {
 			for country, prob := range nameData.Country {
 				countryScores[country] += float64(prob)
@@ -268,185 +914,514 @@ func (s *Scorer) GetTopCountry(combo types.NameCombination) string {
 		}
 	}
 
-	// Add scores from surnames
-	for _, name := range combo.Surnames {
-		// Try dual lookup: first exact case match, then normalized
-		exactKey := strings.ToUpper(strings.TrimSpace(name))
-		nameData, exists := s.dataset.LastNames[exactKey]
+	return countryScores
+}
 
-		if !exists {
-			// Try with accent normalization
-			normalizedKey := normalizeForLookup(name)
-			if normalizedKey != exactKey {
-				nameData, exists = s.dataset.LastNames[normalizedKey]
-			}
+// Gender predictions returned by GetGender besides "Male" and "Female"
+const (
+	genderUnisex  = "Unisex"
+	genderUnknown = "Unknown"
+)
+
+// GetGender returns the predicted gender for the first names in a combination
+func (s *Scorer) GetGender(combo types.NameCombination) string {
+	gender, _ := s.PredictGender(combo)
+	return gender
+}
+
+// PredictGender aggregates the gender probabilities of a combination's first
+// names. It returns "Male" or "Female" with the leading gender's share of the
+// aggregate as confidence, "Unisex" when the two shares are within
+// ScoreConfig.UnisexMargin of each other, and "Unknown" with zero confidence
+// when no first name has gender data.
+func (s *Scorer) PredictGender(combo types.NameCombination) (string, float64) {
+	var male, female float64
+	for _, name := range combo.FirstNames {
+		if nameData, exists := s.lookup(name, true); exists {
+			male += float64(nameData.Gender["M"])
+			female += float64(nameData.Gender["F"])
 		}
+	}
 
-		if exists {
-			for country, prob := range nameData.Country {
-				countryScores[country] += float64(prob)
+	total := male + female
+	if total == 0 {
+		return genderUnknown, 0.0
+	}
+
+	gender, share := "Male", male/total
+	if female > male {
+		gender, share = "Female", female/total
+	}
+	if math.Abs(male-female)/total <= s.config.UnisexMargin {
+		gender = genderUnisex
+	}
+
+	return gender, share
+}
+
+// OriginUnknown reports whether a combination has matched names but none of
+// their entries carry country data, as opposed to country data that is
+// spread across many countries
+func (s *Scorer) OriginUnknown(combo types.NameCombination) bool {
+	matched := false
+	for _, side := range []struct {
+		names       []string
+		isFirstName bool
+	}{{combo.FirstNames, true}, {combo.Surnames, false}} {
+		for _, name := range side.names {
+			nameData, exists := s.lookup(name, side.isFirstName)
+			if !exists {
+				continue
+			}
+			if len(nameData.Country) > 0 {
+				return false
 			}
+			matched = true
 		}
 	}
+	return matched
+}
 
-	// Find the country with highest score
-	var topCountry string
-	var maxScore float64
-	for country, score := range countryScores {
-		if score > maxScore {
-			maxScore = score
-			topCountry = country
+// countMissingCountry counts the entries without any country data
+func countMissingCountry(nameDataList []*types.NameData) int {
+	count := 0
+	for _, nameData := range nameDataList {
+		if len(nameData.Country) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// AmbiguousTokens returns the tokens of a combination that exist in both the
+// first name and surname maps, whose role is therefore inherently uncertain
+func (s *Scorer) AmbiguousTokens(combo types.NameCombination) []string {
+	var ambiguous []string
+
+	for _, names := range [][]string{combo.FirstNames, combo.Surnames} {
+		for _, name := range names {
+			_, isFirst := s.lookup(name, true)
+			_, isLast := s.lookup(name, false)
+			if isFirst && isLast {
+				ambiguous = append(ambiguous, name)
+			}
 		}
 	}
 
-	return topCountry
+	return ambiguous
 }
 
-// GetGender returns the predicted gender for the first names in a combination
-func (s *Scorer) GetGender(combo types.NameCombination) string {
-	if len(combo.FirstNames) == 0 {
-		return ""
+// MatchDetails describes each token of a combination that was found in the
+// dataset for its role: its best rank, its popularity contribution to the
+// score and the lookup that found it, in combination order
+func (s *Scorer) MatchDetails(combo types.NameCombination) []types.NameMatchDetail {
+	var details []types.NameMatchDetail
+	for _, side := range []struct {
+		names []string
+		role  string
+	}{{combo.FirstNames, "first_name"}, {combo.Surnames, "surname"}} {
+		for _, name := range side.names {
+			nameData, method := s.lookupWithMethod(name, side.role == "first_name")
+			if method == "" {
+				continue
+			}
+
+			var rank int32
+			if minRank := s.getMinRankFromData(nameData); minRank != 999999 {
+				rank = minRank
+			}
+			details = append(details, types.NameMatchDetail{
+				Token:      name,
+				Role:       side.role,
+				Rank:       rank,
+				Popularity: s.calculatePopularityScore(nameData, side.role == "first_name") * s.config.PopularityWeight,
+				Lookup:     method,
+			})
+		}
 	}
+	return details
+}
+
+// ComponentScores returns the score of each token of a combination in its
+// role, in combination order. A single-token combination is scored by
+// popularity alone, as in mononymScore.
+func (s *Scorer) ComponentScores(combo types.NameCombination) []types.ComponentScore {
+	mononym := len(combo.FirstNames)+len(combo.Surnames) == 1
+
+	var components []types.ComponentScore
+	for _, side := range []struct {
+		names []string
+		role  string
+	}{{combo.FirstNames, "first_name"}, {combo.Surnames, "surname"}} {
+		for _, name := range side.names {
+			component := types.ComponentScore{Token: name, Role: side.role}
+			isFirstName := side.role == "first_name"
+			if nameData, method, distance := s.lookupMatch(name, isFirstName); method != "" {
+				weight := s.config.Fuzzy.weight(distance)
+				component.Matched = true
+				if mononym {
+					component.PopularityScore = s.calculatePopularityScore(nameData, isFirstName) * weight
+				} else {
+					component.BaseScore = s.config.BaseMatchScore * weight
+					component.PopularityScore = s.calculatePopularityScore(nameData, isFirstName) * s.config.PopularityWeight * weight
+				}
+				component.Score = component.BaseScore + component.PopularityScore
+			}
+			components = append(components, component)
+		}
+	}
+	return components
+}
 
-	genderScores := make(map[string]float64)
+// MatchedNames returns the first names and surnames of a combination that
+// were found in the dataset for their role, in combination order
+func (s *Scorer) MatchedNames(combo types.NameCombination) (firstNames, surnames []string) {
+	for _, name := range combo.FirstNames {
+		if _, exists := s.lookup(name, true); exists {
+			firstNames = append(firstNames, name)
+		}
+	}
+	for _, name := range combo.Surnames {
+		if _, exists := s.lookup(name, false); exists {
+			surnames = append(surnames, name)
+		}
+	}
+	return firstNames, surnames
+}
 
-	// Aggregate gender scores from all first names
+// GenericSurnameOnly reports whether the only evidence for a combination is
+// common surnames: no first name matched, at least one surname did, and every
+// matched surname ranks at or better than maxRank somewhere. It returns the
+// first such surname.
+func (s *Scorer) GenericSurnameOnly(combo types.NameCombination, maxRank int32) (string, bool) {
 	for _, name := range combo.FirstNames {
-		// Try dual lookup: first exact case match, then normalized
-		exactKey := strings.ToUpper(strings.TrimSpace(name))
-		nameData, exists := s.dataset.FirstNames[exactKey]
+		if _, exists := s.lookup(name, true); exists {
+			return "", false
+		}
+	}
 
+	var generic string
+	for _, name := range combo.Surnames {
+		nameData, exists := s.lookup(name, false)
 		if !exists {
-			// Try with accent normalization
-			normalizedKey := normalizeForLookup(name)
-			if normalizedKey != exactKey {
-				nameData, exists = s.dataset.FirstNames[normalizedKey]
-			}
+			continue
+		}
+		if s.getMinRankFromData(nameData) > maxRank {
+			return "", false
 		}
+		if generic == "" {
+			generic = name
+		}
+	}
 
-		if exists {
-			for gender, prob := range nameData.Gender {
-				genderScores[gender] += float64(prob)
+	return generic, generic != ""
+}
+
+// SideConfidences returns the average match score of a combination's first
+// names and of its surnames, each clamped to [0, 1]. A side with no tokens
+// scores 0.
+func (s *Scorer) SideConfidences(combo types.NameCombination) (firstName, surname float64) {
+	return s.sideConfidence(combo.FirstNames, true), s.sideConfidence(combo.Surnames, false)
+}
+
+// sideConfidence averages scoreNames over every token on one side
+func (s *Scorer) sideConfidence(names []string, isFirstNames bool) float64 {
+	if len(names) == 0 {
+		return 0.0
+	}
+
+	total, _ := s.scoreNames(names, isFirstNames)
+	return math.Max(0.0, math.Min(1.0, total/float64(len(names))))
+}
+
+// RoleFit returns the fraction of matched tokens that fit their assigned role:
+// a first name fits when it ranks at least as well as a first name as it
+// does as a surname (or isn't a surname at all), and vice versa for surnames.
+// It is 0 when no token matched.
+func (s *Scorer) RoleFit(combo types.NameCombination) float64 {
+	var matched, fitting int
+
+	for _, side := range []struct {
+		names       []string
+		isFirstName bool
+	}{{combo.FirstNames, true}, {combo.Surnames, false}} {
+		for _, name := range side.names {
+			roleData, inRole := s.lookup(name, side.isFirstName)
+			if !inRole {
+				continue
+			}
+			matched++
+
+			otherData, inOther := s.lookup(name, !side.isFirstName)
+			if !inOther || s.getMinRankFromData(roleData) <= s.getMinRankFromData(otherData) {
+				fitting++
 			}
 		}
 	}
 
-	// Find the gender with highest score
-	var predictedGender string
-	var maxScore float64
-	for gender, score := range genderScores {
-		if score > maxScore {
-			maxScore = score
-			if gender == "M" {
-				predictedGender = "Male"
-			} else if gender == "F" {
-				predictedGender = "Female"
+	if matched == 0 {
+		return 0.0
+	}
+	return float64(fitting) / float64(matched)
+}
+
+// ClassifyTokens separates tokens that are real but rare names (found in the
+// dataset, best rank beyond the common tiers) from tokens that are not in
+// either the first name or surname map at all
+func (s *Scorer) ClassifyTokens(words []string) (rare, unknown []string) {
+	for _, word := range words {
+		firstData, isFirst := s.lookup(word, true)
+		lastData, isLast := s.lookup(word, false)
+
+		if !isFirst && !isLast {
+			unknown = append(unknown, word)
+			continue
+		}
+
+		bestRank := int32(999999)
+		if isFirst {
+			bestRank = s.getMinRankFromData(firstData)
+		}
+		if isLast {
+			if rank := s.getMinRankFromData(lastData); rank < bestRank {
+				bestRank = rank
 			}
 		}
+
+		if bestRank > rareRankThreshold {
+			rare = append(rare, word)
+		}
 	}
 
-	return predictedGender
+	return rare, unknown
 }
 
 // applyPatternAdjustments applies bonuses and penalties based on name patterns
-func (s *Scorer) applyPatternAdjustments(combo types.NameCombination, baseScore float64) float64 {
+func (s *Scorer) applyPatternAdjustments(combo types.NameCombination, baseScore float64, record func(name string, impact float64, detail string)) float64 {
 	adjustedScore := baseScore
 
 	// Penalty for prepositions in first names (major red flag)
 	for _, name := range combo.FirstNames {
 		if s.isProbablyPreposition(name) {
+			before := adjustedScore
 			adjustedScore *= 0.3 // Heavy penalty for prepositions as first names
+			record("preposition_first_name", adjustedScore-before,
+				fmt.Sprintf("preposition %q used as a first name", name))
 		}
 	}
 
-	// Penalty for prepositions in surnames (less severe)
-	for _, name := range combo.Surnames {
-		if s.isProbablyPreposition(name) {
+	// Penalty for prepositions in surnames that don't lead into a known
+	// surname; particles such as "van" in "van Dijk" are left alone
+	for i, name := range combo.Surnames {
+		if s.isProbablyPreposition(name) && !s.isSurnameParticle(combo.Surnames, i) {
+			before := adjustedScore
 			adjustedScore *= 0.7 // Moderate penalty (some legitimate compound surnames use prepositions)
+			record("preposition_surname", adjustedScore-before,
+				fmt.Sprintf("preposition %q used in a surname", name))
+		}
+	}
+
+	// Bonus when the strongest first name and surname are both top-ranked
+	if multiplier, ok := s.topPairMultiplier(combo); ok {
+		before := adjustedScore
+		adjustedScore *= multiplier
+		record("top_pair", adjustedScore-before, "strongest first name and surname are both top-ranked")
+	}
+
+	// Cap combinations whose matches are all in the rarest tier
+	if floor := s.config.NoiseFloor; floor.MinRank > 0 && adjustedScore > floor.MaxScore && s.onlyRareMatches(combo, floor.MinRank) {
+		record("noise_floor", floor.MaxScore-adjustedScore,
+			fmt.Sprintf("every matched name ranks beyond %d", floor.MinRank))
+		adjustedScore = floor.MaxScore
+	}
+
+	return adjustedScore
+}
+
+// onlyRareMatches reports whether combo has at least one name found in its
+// role and every such name ranks beyond minRank
+func (s *Scorer) onlyRareMatches(combo types.NameCombination, minRank int32) bool {
+	matched := 0
+	for _, role := range []struct {
+		names       []string
+		isFirstName bool
+	}{{combo.FirstNames, true}, {combo.Surnames, false}} {
+		for _, name := range role.names {
+			nameData, exists := s.lookup(name, role.isFirstName)
+			if !exists {
+				continue
+			}
+			if s.getMinRankFromData(nameData) <= minRank {
+				return false
+			}
+			matched++
 		}
 	}
+	return matched > 0
+}
 
-	// Bonus for two-word names where both components are top-ranked
+// topPairMultiplier returns the multiplier of the strictest TopPairTiers
+// entry that both the best-ranked first name and best-ranked surname fall in
+func (s *Scorer) topPairMultiplier(combo types.NameCombination) (float64, bool) {
+	var firstRank, lastRank int32
 	if len(combo.FirstNames) == 1 && len(combo.Surnames) == 1 {
-		firstRank := s.getMinRank(combo.FirstNames[0])
-		lastRank := s.getMinRank(combo.Surnames[0])
-		
-		// Both are top-100 names - likely a legitimate person
-		if firstRank <= 100 && lastRank <= 100 {
-			adjustedScore *= 1.4 // Significant boost for common name pairs
-		} else if firstRank <= 10 && lastRank <= 10 {
-			adjustedScore *= 1.6 // Extra boost for top-10 name pairs
+		// Two-token names keep the original lookup, which doesn't check roles
+		firstRank = s.getMinRank(combo.FirstNames[0])
+		lastRank = s.getMinRank(combo.Surnames[0])
+	} else {
+		if !s.allTokensInRole(combo) {
+			return 0, false
 		}
+		firstRank = s.bestRank(combo.FirstNames, true)
+		lastRank = s.bestRank(combo.Surnames, false)
 	}
 
-	return adjustedScore
+	var best *TopPairTier
+	for i := range s.config.TopPairTiers {
+		tier := &s.config.TopPairTiers[i]
+		if firstRank > tier.MaxRank || lastRank > tier.MaxRank {
+			continue
+		}
+		if best == nil || tier.MaxRank < best.MaxRank {
+			best = tier
+		}
+	}
+
+	if best == nil {
+		return 0, false
+	}
+	return best.Multiplier, true
 }
 
-// getMinRank gets the minimum (best) rank for a name across all countries
-func (s *Scorer) getMinRank(name string) int32 {
-	// Try dual lookup like in scoreNames
-	exactKey := strings.ToUpper(strings.TrimSpace(name))
-	
-	// Check first names
-	if nameData, exists := s.dataset.FirstNames[exactKey]; exists {
-		return s.getMinRankFromData(nameData)
+// allTokensInRole reports whether every token is found in the map of its
+// assigned role, apart from surname particles
+func (s *Scorer) allTokensInRole(combo types.NameCombination) bool {
+	for _, name := range combo.FirstNames {
+		if _, exists := s.lookup(name, true); !exists {
+			return false
+		}
 	}
-	
-	// Try normalized lookup
-	normalizedKey := normalizeForLookup(name)
-	if normalizedKey != exactKey {
-		if nameData, exists := s.dataset.FirstNames[normalizedKey]; exists {
-			return s.getMinRankFromData(nameData)
+	for i, name := range combo.Surnames {
+		if s.isProbablyPreposition(name) && s.isSurnameParticle(combo.Surnames, i) {
+			continue
+		}
+		if _, exists := s.lookup(name, false); !exists {
+			return false
 		}
 	}
-	
-	// Check last names
-	if nameData, exists := s.dataset.LastNames[exactKey]; exists {
-		return s.getMinRankFromData(nameData)
+	return true
+}
+
+// bestRank returns the best (lowest) rank among the given names, using their
+// data for the given role
+func (s *Scorer) bestRank(names []string, isFirstName bool) int32 {
+	best := int32(999999)
+	for _, name := range names {
+		nameData, exists := s.lookup(name, isFirstName)
+		if !exists {
+			continue
+		}
+		if rank := s.getMinRankFromData(nameData); rank < best {
+			best = rank
+		}
 	}
-	
-	// Try normalized lookup for last names
-	if normalizedKey != exactKey {
-		if nameData, exists := s.dataset.LastNames[normalizedKey]; exists {
-			return s.getMinRankFromData(nameData)
+	return best
+}
+
+// averageRank returns the mean of the names' best ranks for the given role,
+// counting names missing from the dataset as unranked
+func (s *Scorer) averageRank(names []string, isFirstName bool) float64 {
+	if len(names) == 0 {
+		return 999999
+	}
+
+	var total float64
+	for _, name := range names {
+		rank := int32(999999)
+		if nameData, exists := s.lookup(name, isFirstName); exists {
+			rank = s.getMinRankFromData(nameData)
 		}
+		total += float64(rank)
 	}
-	
+	return total / float64(len(names))
+}
+
+// breaksTie reports whether combo should replace best when their scores tie:
+// the split whose first names are better ranked as first names wins, then
+// the one with fewer first names. Otherwise the earlier combination stays,
+// so forward splits keep priority over surname-first ones.
+func (s *Scorer) breaksTie(combo, best types.NameCombination) bool {
+	comboRank := s.averageRank(combo.FirstNames, true)
+	bestRank := s.averageRank(best.FirstNames, true)
+	if comboRank != bestRank {
+		return comboRank < bestRank
+	}
+	return len(combo.FirstNames) < len(best.FirstNames)
+}
+
+// getMinRank gets the minimum (best) rank for a name across all countries
+func (s *Scorer) getMinRank(name string) int32 {
+	// Check first names, then last names
+	if nameData, exists := s.lookup(name, true); exists {
+		return s.getMinRankFromData(nameData)
+	}
+	if nameData, exists := s.lookup(name, false); exists {
+		return s.getMinRankFromData(nameData)
+	}
+
 	return 999999 // Not found
 }
 
 // getMinRankFromData extracts the minimum rank from NameData
 func (s *Scorer) getMinRankFromData(nameData *types.NameData) int32 {
-	if len(nameData.Rank) == 0 {
-		return 999999
+	if minRank := minRankOf(nameData); minRank > 0 {
+		return minRank
+	}
+	return 999999
+}
+
+// minRankOf returns the precomputed MinRank of nameData, scanning Rank when
+// it wasn't filled in, or 0 when the name has no rank
+func minRankOf(nameData *types.NameData) int32 {
+	if nameData.MinRank > 0 {
+		return nameData.MinRank
+	}
+	return types.BestRank(nameData.Rank)
+}
+
+// isSurnameParticle reports whether the preposition at surnames[i] leads
+// into a surname that isn't rare, skipping further prepositions, as "de" and
+// "la" do in "de la Cruz". Rare surnames are excluded so that business terms
+// such as "Informe de Cliente" keep their penalty.
+func (s *Scorer) isSurnameParticle(surnames []string, i int) bool {
+	for _, name := range surnames[i+1:] {
+		if !s.isProbablyPreposition(name) {
+			nameData, exists := s.lookup(name, false)
+			return exists && s.getMinRankFromData(nameData) <= rareRankThreshold
+		}
 	}
-	
-	minRank := int32(999999)
-	for _, rank := range nameData.Rank {
-		if rank > 0 && rank < minRank {
-			minRank = rank
+	return false
+}
+
+// countSurnameParticles counts the prepositions missing from the surname
+// map that lead into a known surname. They connect a compound surname rather
+// than being names themselves, so they don't count as unknown tokens when
+// averaging.
+func (s *Scorer) countSurnameParticles(surnames []string) int {
+	count := 0
+	for i, name := range surnames {
+		if !s.isProbablyPreposition(name) || !s.isSurnameParticle(surnames, i) {
+			continue
+		}
+		if _, exists := s.lookup(name, false); !exists {
+			count++
 		}
 	}
-	
-	return minRank
+	return count
 }
 
-// isProbablyPreposition checks if a word is likely a preposition (used by scorer)
+// isProbablyPreposition checks if a word is one of the configured
+// prepositions (used by scorer)
 func (s *Scorer) isProbablyPreposition(word string) bool {
-	lowerWord := strings.ToLower(word)
-	prepositions := map[string]bool{
-		// Spanish
-		"de": true, "del": true, "la": true, "el": true,
-		"los": true, "las": true, "y": true,
-		// Portuguese
-		"da": true, "do": true, "dos": true, "das": true,
-		// French
-		"du": true, "le": true, "les": true,
-		// Dutch/German
-		"van": true, "von": true, "der": true, "den": true,
-		// English
-		"of": true, "and": true,
-	}
-	
-	return prepositions[lowerWord]
-}
\ No newline at end of file
+	return s.config.Prepositions[strings.ToLower(word)]
+}
@@ -0,0 +1,89 @@
+package detector
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+// ErrUnknownDataset is returned by DetectorSet when no detector is registered
+// for the requested dataset and there is no fallback
+var ErrUnknownDataset = errors.New("no detector for dataset")
+
+// DetectorSet routes detections to one of several detectors, each scoring
+// against its own dataset, such as one per region ("eu", "latam", "apac").
+// Dataset names are matched case-insensitively. A request for a name with no
+// detector goes to the fallback detector when one is set, and fails with
+// ErrUnknownDataset otherwise. A DetectorSet is safe for concurrent use.
+type DetectorSet struct {
+	detectors map[string]*Detector
+	fallback  *Detector
+}
+
+// NewDetectorSet returns a set routing to detectors by dataset name.
+// fallback names the detector used for unknown names; empty disables the
+// fallback. It returns ErrUnknownDataset when fallback isn't in detectors.
+func NewDetectorSet(detectors map[string]*Detector, fallback string) (*DetectorSet, error) {
+	set := &DetectorSet{detectors: make(map[string]*Detector, len(detectors))}
+	for name, d := range detectors {
+		set.detectors[datasetKey(name)] = d
+	}
+
+	if fallback != "" {
+		d, exists := set.detectors[datasetKey(fallback)]
+		if !exists {
+			return nil, fmt.Errorf("%w: fallback %q", ErrUnknownDataset, fallback)
+		}
+		set.fallback = d
+	}
+	return set, nil
+}
+
+// NewDetectorSetFromDatasets is NewDetectorSet with a detector created with
+// the given configurations for each dataset
+func NewDetectorSetFromDatasets(datasets map[string]*types.NameDataset, scoreConfig ScoreConfig, config DetectorConfig, fallback string) (*DetectorSet, error) {
+	detectors := make(map[string]*Detector, len(datasets))
+	for name, dataset := range datasets {
+		detectors[name] = NewWithDetectorConfig(dataset, scoreConfig, config)
+	}
+	return NewDetectorSet(detectors, fallback)
+}
+
+// datasetKey returns the case-insensitive key of a dataset name
+func datasetKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Detector returns the detector for dataset, or the fallback detector when
+// there is none. The boolean is false when neither exists.
+func (s *DetectorSet) Detector(dataset string) (*Detector, bool) {
+	if d, exists := s.detectors[datasetKey(dataset)]; exists {
+		return d, true
+	}
+	return s.fallback, s.fallback != nil
+}
+
+// Datasets returns the dataset names in the set, lowercased and sorted
+func (s *DetectorSet) Datasets() []string {
+	return slices.Sorted(maps.Keys(s.detectors))
+}
+
+// DetectPII analyzes words with the detector for dataset
+func (s *DetectorSet) DetectPII(dataset string, words []string) (types.PIIResult, error) {
+	return s.DetectPIIWithThreshold(dataset, words, 0.7) // Default threshold
+}
+
+// DetectPIIWithThreshold analyzes words with the detector for dataset and a
+// custom confidence threshold. It returns ErrUnknownDataset when the set has
+// no detector for dataset and no fallback.
+func (s *DetectorSet) DetectPIIWithThreshold(dataset string, words []string, threshold float64) (types.PIIResult, error) {
+	d, ok := s.Detector(dataset)
+	if !ok {
+		return types.PIIResult{}, fmt.Errorf("%w: %q", ErrUnknownDataset, dataset)
+	}
+	return d.DetectPIIWithThreshold(words, threshold), nil
+}
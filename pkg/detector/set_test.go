@@ -0,0 +1,82 @@
+package detector
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+func createAndeanDataset() *types.NameDataset {
+	return &types.NameDataset{
+		FirstNames: map[string]*types.NameData{
+			"XIMENA": {
+				Country: map[string]float32{"PE": 0.6, "BO": 0.4},
+				Gender:  map[string]float32{"F": 1.0},
+				Rank:    map[string]int32{"PE": 5, "BO": 8},
+			},
+		},
+		LastNames: map[string]*types.NameData{
+			"QUISPE": {
+				Country: map[string]float32{"PE": 0.7, "BO": 0.3},
+				Rank:    map[string]int32{"PE": 1, "BO": 2},
+			},
+		},
+	}
+}
+
+func TestDetectorSet(t *testing.T) {
+	set, err := NewDetectorSetFromDatasets(map[string]*types.NameDataset{
+		"EU":    createTestDataset(),
+		"latam": createAndeanDataset(),
+	}, DefaultScoreConfig(), DefaultDetectorConfig(), "")
+	if err != nil {
+		t.Fatalf("NewDetectorSetFromDatasets failed: %v", err)
+	}
+
+	if got := set.Datasets(); !equalStringSlices(got, []string{"eu", "latam"}) {
+		t.Errorf("Expected datasets [eu latam], got %v", got)
+	}
+
+	// Each dataset only knows its own names
+	result, err := set.DetectPII("latam", []string{"Ximena", "Quispe"})
+	if err != nil || !result.IsLikelyName {
+		t.Errorf("Expected Ximena Quispe detected with the latam dataset, got %.3f (%v)", result.Confidence, err)
+	}
+	result, err = set.DetectPII("eu", []string{"Ximena", "Quispe"})
+	if err != nil || result.IsLikelyName {
+		t.Errorf("Expected Ximena Quispe not detected with the eu dataset, got %.3f (%v)", result.Confidence, err)
+	}
+	if result, err := set.DetectPII("Eu", []string{"Jose", "Garcia"}); err != nil || !result.IsLikelyName {
+		t.Errorf("Expected case-insensitive dataset names, got %.3f (%v)", result.Confidence, err)
+	}
+
+	if _, err := set.DetectPII("apac", []string{"Jose", "Garcia"}); !errors.Is(err, ErrUnknownDataset) {
+		t.Errorf("Expected ErrUnknownDataset without a fallback, got %v", err)
+	}
+}
+
+func TestDetectorSet_Fallback(t *testing.T) {
+	eu := New(createTestDataset())
+	latam := New(createAndeanDataset())
+
+	set, err := NewDetectorSet(map[string]*Detector{"eu": eu, "latam": latam}, "eu")
+	if err != nil {
+		t.Fatalf("NewDetectorSet failed: %v", err)
+	}
+
+	if d, ok := set.Detector("apac"); !ok || d != eu {
+		t.Errorf("Expected the eu detector as fallback")
+	}
+	if d, ok := set.Detector("latam"); !ok || d != latam {
+		t.Errorf("Expected the latam detector")
+	}
+	result, err := set.DetectPIIWithThreshold("apac", []string{"Jose", "Garcia"}, 0.7)
+	if err != nil || !result.IsLikelyName {
+		t.Errorf("Expected Jose Garcia detected through the fallback, got %.3f (%v)", result.Confidence, err)
+	}
+
+	if _, err := NewDetectorSet(map[string]*Detector{"eu": eu}, "apac"); !errors.Is(err, ErrUnknownDataset) {
+		t.Errorf("Expected ErrUnknownDataset for a missing fallback, got %v", err)
+	}
+}
@@ -0,0 +1,278 @@
+package detector
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+// SegmentFunc receives the detection result for one scanned segment. Index
+// counts segments from zero. Returning an error stops scanning.
+type SegmentFunc func(index int, segment string, result types.PIIResult) error
+
+// DetectFromScanner runs detection on every segment produced by sc, splitting
+// each segment into words on whitespace. The caller controls segmentation
+// through the scanner's SplitFunc (lines, records, custom delimiters), and
+// only one segment is held in memory at a time. Blank segments are skipped.
+func (d *Detector) DetectFromScanner(sc *bufio.Scanner, threshold float64, fn SegmentFunc) error {
+	return d.DetectFromScannerContext(context.Background(), sc, threshold, fn)
+}
+
+// DetectFromScannerContext is DetectFromScanner, returning ErrCanceled as
+// soon as ctx is done
+func (d *Detector) DetectFromScannerContext(ctx context.Context, sc *bufio.Scanner, threshold float64, fn SegmentFunc) error {
+	index := 0
+	for sc.Scan() {
+		segment := sc.Text()
+		words := strings.Fields(segment)
+		if len(words) == 0 {
+			continue
+		}
+
+		result, err := d.DetectPIIContext(ctx, words, threshold)
+		if err != nil {
+			return err
+		}
+		if err := fn(index, segment, result); err != nil {
+			return err
+		}
+		index++
+	}
+
+	return sc.Err()
+}
+
+// StreamFormat selects how DetectStream writes its results
+type StreamFormat int
+
+const (
+	// StreamJSONLines writes one JSON object per line with the line number,
+	// the input and the full PIIResult
+	StreamJSONLines StreamFormat = iota
+
+	// StreamTSV writes a header row followed by one tab-separated row per
+	// line: line number, PII or NOT_PII, confidence and the input words
+	StreamTSV
+
+	// StreamJSONIndented writes the same objects as StreamJSONLines, indented
+	// over several lines for human inspection
+	StreamJSONIndented
+
+	// StreamCSV writes a header row followed by one RFC 4180 row per line:
+	// line number, input, whether it is PII, confidence, first names,
+	// surnames, pattern, top country and gender. Names are joined with
+	// csvNameSeparator.
+	StreamCSV
+)
+
+// csvHeader is the header row written by StreamCSV
+var csvHeader = []string{"line", "input", "is_pii", "confidence", "first_names", "surnames", "pattern", "top_country", "gender"}
+
+// csvNameSeparator joins the first names and the surnames of a StreamCSV
+// row. Names can contain spaces ("St. John") but not semicolons.
+const csvNameSeparator = ";"
+
+// maxStreamLine is the longest input line DetectStream accepts
+const maxStreamLine = 1024 * 1024
+
+// StreamOptions controls DetectStream
+type StreamOptions struct {
+	Threshold float64      // Confidence threshold for PII detection
+	Format    StreamFormat // Output format
+
+	// OnResult, when set, is called after each result is written, with the
+	// 1-based line number as its index. Returning an error stops the stream.
+	OnResult SegmentFunc
+}
+
+// DefaultStreamOptions returns the default streaming options: JSON lines at
+// the default threshold
+func DefaultStreamOptions() StreamOptions {
+	return StreamOptions{
+		Threshold: 0.7,
+		Format:    StreamJSONLines,
+	}
+}
+
+// streamRecord is the JSON lines representation of one result
+type streamRecord struct {
+	Line   int             `json:"line"`
+	Input  string          `json:"input"`
+	Result types.PIIResult `json:"result"`
+}
+
+// csvRow formats one result as a StreamCSV row
+func csvRow(lineNumber int, input string, result types.PIIResult) []string {
+	return []string{
+		strconv.Itoa(lineNumber),
+		input,
+		strconv.FormatBool(result.IsLikelyName),
+		strconv.FormatFloat(result.Confidence, 'f', 4, 64),
+		strings.Join(result.Details.FirstNames, csvNameSeparator),
+		strings.Join(result.Details.Surnames, csvNameSeparator),
+		result.Details.Pattern,
+		result.Details.TopCountry,
+		result.Details.Gender,
+	}
+}
+
+// DetectStream reads r line by line, runs detection on the words of every
+// non-blank line and writes each result to w as soon as it is scored, so
+// memory use doesn't grow with the input. Lines with fewer words than
+// DetectorConfig.MinWords or more than MaxWords are skipped without a
+// result. Lines longer than 1 MiB are rejected with an error.
+func (d *Detector) DetectStream(r io.Reader, w io.Writer, opts StreamOptions) error {
+	return d.DetectStreamContext(context.Background(), r, w, opts)
+}
+
+// DetectStreamContext is DetectStream, returning ErrCanceled as soon as ctx
+// is done. Results scored before cancellation are flushed to w.
+func (d *Detector) DetectStreamContext(ctx context.Context, r io.Reader, w io.Writer, opts StreamOptions) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxStreamLine)
+
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	if opts.Format == StreamJSONIndented {
+		encoder.SetIndent("", "  ")
+	}
+
+	csvWriter := csv.NewWriter(bw)
+
+	switch opts.Format {
+	case StreamTSV:
+		if _, err := fmt.Fprintln(bw, "line\tstatus\tconfidence\tinput"); err != nil {
+			return err
+		}
+	case StreamCSV:
+		if err := csvWriter.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+
+	lineNumber := 0
+	for sc.Scan() {
+		lineNumber++
+		input := strings.TrimSpace(sc.Text())
+		words := strings.Fields(input)
+		if len(words) == 0 || !d.acceptsWordCount(len(words)) {
+			continue
+		}
+
+		result, err := d.DetectPIIContext(ctx, words, opts.Threshold)
+		if err != nil {
+			bw.Flush()
+			return err
+		}
+
+		switch opts.Format {
+		case StreamTSV:
+			status := "NOT_PII"
+			if result.IsLikelyName {
+				status = "PII"
+			}
+			_, err = fmt.Fprintf(bw, "%d\t%s\t%.4f\t%s\n", lineNumber, status, result.Confidence, strings.Join(words, " "))
+		case StreamCSV:
+			err = csvWriter.Write(csvRow(lineNumber, input, result))
+			csvWriter.Flush()
+			if err == nil {
+				err = csvWriter.Error()
+			}
+		default:
+			err = encoder.Encode(streamRecord{Line: lineNumber, Input: input, Result: result})
+		}
+		if err != nil {
+			return err
+		}
+
+		if opts.OnResult != nil {
+			if err := opts.OnResult(lineNumber, input, result); err != nil {
+				bw.Flush()
+				return err
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		bw.Flush()
+		return fmt.Errorf("failed to read line %d: %w", lineNumber+1, err)
+	}
+
+	return bw.Flush()
+}
+
+// MatchFunc receives each name found by ScanReader. Returning an error stops
+// the scan.
+type MatchFunc func(match types.NameMatch) error
+
+// Scan finds names in the free-form text read from r, such as log files, and
+// calls fn for each match. It is shorthand for ScanReader with the default
+// scan options at the given threshold.
+func (d *Detector) Scan(r io.Reader, threshold float64, fn MatchFunc) error {
+	opts := DefaultScanOptions()
+	opts.Threshold = threshold
+	return d.ScanReader(r, opts, fn)
+}
+
+// ScanReader reads r line by line, scans each line like ScanText and calls fn
+// for every match as soon as its line is scanned, so memory use doesn't grow
+// with the input. Match offsets are byte offsets from the start of r. Lines
+// longer than opts.MaxLineSize are rejected with an error.
+func (d *Detector) ScanReader(r io.Reader, opts ScanOptions, fn MatchFunc) error {
+	return d.ScanReaderContext(context.Background(), r, opts, fn)
+}
+
+// ScanReaderContext is ScanReader, returning ErrCanceled as soon as ctx is
+// done. Matches already passed to fn are not retracted.
+func (d *Detector) ScanReaderContext(ctx context.Context, r io.Reader, opts ScanOptions, fn MatchFunc) error {
+	maxLine := opts.MaxLineSize
+	if maxLine <= 0 {
+		maxLine = maxStreamLine
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, min(64*1024, maxLine)), maxLine)
+
+	// Track where each line starts in the stream; ScanLines drops the line
+	// terminator, so the offset can't be derived from the token alone
+	var lineStart, consumed int
+	sc.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if token != nil {
+			lineStart = consumed
+			consumed += advance
+		}
+		return advance, token, err
+	})
+
+	lineNumber := 0
+	for sc.Scan() {
+		lineNumber++
+		if err := canceled(ctx); err != nil {
+			return err
+		}
+
+		matches, err := d.ScanTextContext(ctx, sc.Text(), opts)
+		if err != nil {
+			return err
+		}
+		for _, match := range matches {
+			match.Start += lineStart
+			match.End += lineStart
+			if err := fn(match); err != nil {
+				return err
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("failed to read line %d: %w", lineNumber+1, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,258 @@
+package detector
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+// splitOnSemicolon is a bufio.SplitFunc that splits records on ';'
+func splitOnSemicolon(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexByte(data, ';'); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+func TestDetectFromScanner(t *testing.T) {
+	detector := New(createTestDataset())
+
+	sc := bufio.NewScanner(strings.NewReader("Jose Garcia; Quick Brown Fox;  ;John Smith"))
+	sc.Split(splitOnSemicolon)
+
+	var segments []string
+	var detected []bool
+	err := detector.DetectFromScanner(sc, 0.7, func(index int, segment string, result types.PIIResult) error {
+		if index != len(segments) {
+			t.Errorf("Expected segment index %d, got %d", len(segments), index)
+		}
+		segments = append(segments, strings.TrimSpace(segment))
+		detected = append(detected, result.IsLikelyName)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !equalStringSlices(segments, []string{"Jose Garcia", "Quick Brown Fox", "John Smith"}) {
+		t.Errorf("Unexpected segments %q", segments)
+	}
+	if len(detected) != 3 || !detected[0] || detected[1] || !detected[2] {
+		t.Errorf("Unexpected detections %v", detected)
+	}
+}
+
+func TestDetectFromScanner_StopsOnError(t *testing.T) {
+	detector := New(createTestDataset())
+	stop := errors.New("stop")
+
+	calls := 0
+	err := detector.DetectFromScanner(bufio.NewScanner(strings.NewReader("Jose Garcia\nJohn Smith\n")), 0.7,
+		func(int, string, types.PIIResult) error {
+			calls++
+			return stop
+		})
+
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Expected scanning to stop after the first error, got err=%v calls=%d", err, calls)
+	}
+}
+
+func TestDetectStream(t *testing.T) {
+	detector := New(createTestDataset())
+	input := "Jose Garcia\n\n  Quick Brown Fox\nJohn\tSmith\n"
+
+	var out bytes.Buffer
+	var lines []int
+	opts := DefaultStreamOptions()
+	opts.OnResult = func(line int, _ string, _ types.PIIResult) error {
+		lines = append(lines, line)
+		return nil
+	}
+	if err := detector.DetectStream(strings.NewReader(input), &out, opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !equalIntSlices(lines, []int{1, 3, 4}) {
+		t.Errorf("Expected results for lines [1 3 4], got %v", lines)
+	}
+
+	decoder := json.NewDecoder(&out)
+	var records []streamRecord
+	for decoder.More() {
+		var record streamRecord
+		if err := decoder.Decode(&record); err != nil {
+			t.Fatalf("Invalid JSON line: %v", err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 3 || records[1].Input != "Quick Brown Fox" || records[1].Result.IsLikelyName || !records[2].Result.IsLikelyName {
+		t.Errorf("Unexpected JSON lines %+v", records)
+	}
+
+	// Each record is a single compact line, unless indented
+	out.Reset()
+	if err := detector.DetectStream(strings.NewReader(input), &out, DefaultStreamOptions()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 3 {
+		t.Errorf("Expected 3 JSON lines, got %d", len(lines))
+	}
+	out.Reset()
+	opts = DefaultStreamOptions()
+	opts.Format = StreamJSONIndented
+	if err := detector.DetectStream(strings.NewReader(input), &out, opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	decoder = json.NewDecoder(&out)
+	count := 0
+	for decoder.More() {
+		var record streamRecord
+		if err := decoder.Decode(&record); err != nil {
+			t.Fatalf("Invalid indented JSON: %v", err)
+		}
+		count++
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 indented records, got %d", count)
+	}
+
+	out.Reset()
+	opts = DefaultStreamOptions()
+	opts.Format = StreamTSV
+	if err := detector.DetectStream(strings.NewReader(input), &out, opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rows := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(rows) != 4 || rows[0] != "line\tstatus\tconfidence\tinput" {
+		t.Fatalf("Expected a header and 3 rows, got %q", rows)
+	}
+	if fields := strings.Split(rows[3], "\t"); len(fields) != 4 || fields[0] != "4" || fields[1] != "PII" || fields[3] != "John Smith" {
+		t.Errorf("Unexpected TSV row %q", rows[3])
+	}
+}
+
+func TestDetectStream_SkipsWordCountOutOfRange(t *testing.T) {
+	input := "John Smith\nHello\na b c d e f g h\n"
+
+	streamLines := func(detector *Detector) []int {
+		var lines []int
+		opts := DefaultStreamOptions()
+		opts.OnResult = func(line int, _ string, _ types.PIIResult) error {
+			lines = append(lines, line)
+			return nil
+		}
+		var out bytes.Buffer
+		if err := detector.DetectStream(strings.NewReader(input), &out, opts); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if records := strings.Count(out.String(), "\n"); records != len(lines) {
+			t.Errorf("Expected %d records, got %d", len(lines), records)
+		}
+		return lines
+	}
+
+	if lines := streamLines(New(createTestDataset())); !equalIntSlices(lines, []int{1}) {
+		t.Errorf("Expected only line 1 within the default word limits, got %v", lines)
+	}
+
+	config := DefaultDetectorConfig()
+	config.MinWords = 1
+	config.MaxWords = 8
+	if lines := streamLines(NewWithDetectorConfig(createTestDataset(), DefaultScoreConfig(), config)); !equalIntSlices(lines, []int{1, 2, 3}) {
+		t.Errorf("Expected every line within the configured word limits, got %v", lines)
+	}
+}
+
+func TestDetectStream_CSV(t *testing.T) {
+	detector := New(createTestDataset())
+	opts := DefaultStreamOptions()
+	opts.Format = StreamCSV
+
+	var out bytes.Buffer
+	input := "Jose Manuel Garcia Lopez\n\nquick, brown fox\n"
+	if err := detector.DetectStream(strings.NewReader(input), &out, opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV: %v", err)
+	}
+	if len(rows) != 3 || strings.Join(rows[0], ",") != "line,input,is_pii,confidence,first_names,surnames,pattern,top_country,gender" {
+		t.Fatalf("Expected a header and 2 rows, got %q", rows)
+	}
+
+	expected := []string{"1", "Jose Manuel Garcia Lopez", "true", "", "Jose;Manuel", "Garcia;Lopez", "2_first_2_last", "", "Male"}
+	for i, field := range expected {
+		if field != "" && rows[1][i] != field {
+			t.Errorf("Column %s: expected %q, got %q", rows[0][i], field, rows[1][i])
+		}
+	}
+	if rows[2][0] != "3" || rows[2][1] != "quick, brown fox" || rows[2][2] != "false" {
+		t.Errorf("Expected the comma in the input to be quoted, got %q", rows[2])
+	}
+}
+
+func TestScan(t *testing.T) {
+	detector := New(createTestDataset())
+	input := "{\"msg\": \"login by Jose Garcia\"}\r\n\nnothing to see here\n{\"msg\": \"John Smith logged out\"}"
+
+	var matches []types.NameMatch
+	err := detector.Scan(strings.NewReader(input), 0.7, func(match types.NameMatch) error {
+		matches = append(matches, match)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"Jose Garcia", "John Smith"}
+	if len(matches) != len(expected) {
+		t.Fatalf("Expected %d matches, got %+v", len(expected), matches)
+	}
+	for i, match := range matches {
+		if match.Text != expected[i] {
+			t.Errorf("Match %d: expected %q, got %q", i, expected[i], match.Text)
+		}
+		if input[match.Start:match.End] != match.Text {
+			t.Errorf("Match %d: stream offsets [%d:%d] don't cover %q", i, match.Start, match.End, match.Text)
+		}
+	}
+}
+
+func TestScan_StopsOnError(t *testing.T) {
+	detector := New(createTestDataset())
+	stop := errors.New("stop")
+
+	calls := 0
+	err := detector.Scan(strings.NewReader("Jose Garcia\nJohn Smith\n"), 0.7, func(types.NameMatch) error {
+		calls++
+		return stop
+	})
+
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Expected scanning to stop after the first error, got err=%v calls=%d", err, calls)
+	}
+}
+
+func TestScanReader_MaxLineSize(t *testing.T) {
+	detector := New(createTestDataset())
+	opts := DefaultScanOptions()
+	opts.MaxLineSize = 16
+
+	err := detector.ScanReader(strings.NewReader("Jose Garcia\n"+strings.Repeat("x", 64)+"\n"), opts,
+		func(types.NameMatch) error { return nil })
+	if !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("Expected bufio.ErrTooLong for a line over the limit, got %v", err)
+	}
+}
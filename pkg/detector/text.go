@@ -0,0 +1,259 @@
+package detector
+
+import (
+	"context"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+// ScanOptions controls how free-form text is scanned for names
+type ScanOptions struct {
+	Threshold float64 // Confidence threshold for a window to count as a name
+	MinWindow int     // Smallest number of tokens per candidate window
+	MaxWindow int     // Largest number of tokens per candidate window
+
+	// Stride is the number of tokens between window start positions. A stride
+	// of 1 tries every position; larger strides scan long documents faster at
+	// the cost of missing names that don't start on a stride boundary.
+	Stride int
+
+	// MaxLineSize is the longest line ScanReader accepts, in bytes. Zero
+	// means 1 MiB.
+	MaxLineSize int
+}
+
+// DefaultScanOptions returns the default text scanning options
+func DefaultScanOptions() ScanOptions {
+	return ScanOptions{
+		Threshold: 0.7,
+		MinWindow: 2,
+		MaxWindow: 6,
+		Stride:    1,
+	}
+}
+
+// textToken is a word found in free-form text with its byte offsets
+type textToken struct {
+	text    string
+	start   int
+	end     int
+	segment int // Windows never span tokens from different segments
+}
+
+// ScanText finds names in free-form text by scoring windows of consecutive
+// tokens. Windows that contain no token known to the dataset are skipped
+// without scoring, so documents with few names are scanned in near-linear
+// time. Overlapping candidates are resolved by keeping the highest-confidence
+// match, and the matches are returned in text order.
+func (d *Detector) ScanText(text string, opts ScanOptions) []types.NameMatch {
+	// A background context is never canceled, so scanning can't fail
+	matches, _ := d.ScanTextContext(context.Background(), text, opts)
+	return matches
+}
+
+// ScanTextContext is ScanText, returning ErrCanceled as soon as ctx is done
+// instead of scoring the remaining windows
+func (d *Detector) ScanTextContext(ctx context.Context, text string, opts ScanOptions) ([]types.NameMatch, error) {
+	if opts.MinWindow < 1 {
+		opts.MinWindow = 1
+	}
+	if opts.MaxWindow < opts.MinWindow {
+		opts.MaxWindow = opts.MinWindow
+	}
+	if opts.Stride < 1 {
+		opts.Stride = 1
+	}
+
+	tokens := tokenizeText(text)
+
+	// Precompute which tokens are in the dataset, as prefix sums so a
+	// window's known-token count is a subtraction
+	known := make([]int, len(tokens)+1)
+	for i, token := range tokens {
+		known[i+1] = known[i]
+		if d.isKnownToken(token.text) {
+			known[i+1]++
+		}
+	}
+
+	var candidates []types.NameMatch
+	for start := 0; start < len(tokens); start += opts.Stride {
+		if err := canceled(ctx); err != nil {
+			return nil, err
+		}
+
+		for size := opts.MinWindow; size <= opts.MaxWindow; size++ {
+			end := start + size
+			if end > len(tokens) || tokens[end-1].segment != tokens[start].segment {
+				break
+			}
+			if known[end] == known[start] {
+				continue
+			}
+
+			window := tokens[start:end]
+			words := make([]string, len(window))
+			for i, token := range window {
+				words[i] = token.text
+			}
+
+			result, err := d.DetectPIIContext(ctx, words, opts.Threshold)
+			if err != nil {
+				return nil, err
+			}
+			if !result.IsLikelyName {
+				continue
+			}
+
+			matchStart, matchEnd := matchSpan(window, result.Details)
+			candidates = append(candidates, types.NameMatch{
+				Start:  matchStart,
+				End:    matchEnd,
+				Text:   text[matchStart:matchEnd],
+				Result: result,
+			})
+		}
+	}
+
+	return selectNonOverlapping(candidates), nil
+}
+
+// DetectNamesInText finds every name in free-form text using the default scan
+// options, returning non-overlapping matches with their byte offsets
+func (d *Detector) DetectNamesInText(text string) []types.NameMatch {
+	return d.ScanText(text, DefaultScanOptions())
+}
+
+// DetectPIIInText is DetectNamesInText with a custom confidence threshold
+func (d *Detector) DetectPIIInText(text string, threshold float64) []types.NameMatch {
+	opts := DefaultScanOptions()
+	opts.Threshold = threshold
+	return d.ScanText(text, opts)
+}
+
+// isKnownToken reports whether a token exists as a first name or surname
+func (d *Detector) isKnownToken(token string) bool {
+	if _, exists := d.scorer.lookup(token, true); exists {
+		return true
+	}
+	_, exists := d.scorer.lookup(token, false)
+	return exists
+}
+
+// matchSpan returns the byte span of the window tokens that ended up in the
+// detected name, so words dropped during cleaning aren't highlighted
+func matchSpan(window []textToken, details types.NameDetails) (int, int) {
+	first, last := len(window), -1
+	for _, indices := range [][]int{details.FirstNameIndices, details.SurnameIndices} {
+		for _, i := range indices {
+			if i < first {
+				first = i
+			}
+			if i > last {
+				last = i
+			}
+		}
+	}
+
+	if last < 0 {
+		return window[0].start, window[len(window)-1].end
+	}
+	return window[first].start, window[last].end
+}
+
+// selectNonOverlapping greedily keeps the highest-confidence candidates
+// (preferring longer, then earlier spans on ties) that don't overlap an
+// already kept match, and returns them in text order
+func selectNonOverlapping(candidates []types.NameMatch) []types.NameMatch {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Result.Confidence != b.Result.Confidence {
+			return a.Result.Confidence > b.Result.Confidence
+		}
+		if a.End-a.Start != b.End-b.Start {
+			return a.End-a.Start > b.End-b.Start
+		}
+		return a.Start < b.Start
+	})
+
+	var selected []types.NameMatch
+	for _, candidate := range candidates {
+		overlaps := false
+		for _, match := range selected {
+			if candidate.Start < match.End && match.Start < candidate.End {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			selected = append(selected, candidate)
+		}
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Start < selected[j].Start
+	})
+
+	return selected
+}
+
+// tokenizeText splits text into word tokens with their byte offsets. Words
+// are runs of letters and combining marks, with apostrophes and hyphens
+// allowed between letters ("O'Brien", "Jean-Pierre"). Punctuation, digits,
+// symbols and line breaks end the current segment, so a window never
+// joins words across a sentence or list boundary.
+func tokenizeText(text string) []textToken {
+	var tokens []textToken
+	segment := 0
+	wordStart := -1
+
+	isWordRune := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r)
+	}
+	isJoiner := func(r rune) bool {
+		return r == '\'' || r == '’' || r == '-'
+	}
+
+	flush := func(end int) {
+		if wordStart >= 0 {
+			tokens = append(tokens, textToken{
+				text:    text[wordStart:end],
+				start:   wordStart,
+				end:     end,
+				segment: segment,
+			})
+			wordStart = -1
+		}
+	}
+
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+
+		switch {
+		case isWordRune(r):
+			if wordStart < 0 {
+				wordStart = i
+			}
+		case isJoiner(r) && wordStart >= 0 && i+size < len(text):
+			// Keep joiners only when another letter follows
+			next, _ := utf8.DecodeRuneInString(text[i+size:])
+			if !isWordRune(next) {
+				flush(i)
+				segment++
+			}
+		case r == '\n' || (!unicode.IsSpace(r) && !isWordRune(r)):
+			flush(i)
+			segment++
+		default:
+			flush(i)
+		}
+
+		i += size
+	}
+	flush(len(text))
+
+	return tokens
+}
@@ -0,0 +1,150 @@
+package detector
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenizeText(t *testing.T) {
+	tokens := tokenizeText("Dear José García, meet O'Brien-Smith.\nThanks")
+
+	expected := []struct {
+		text    string
+		segment int
+	}{
+		{"Dear", 0}, {"José", 0}, {"García", 0},
+		{"meet", 1}, {"O'Brien-Smith", 1},
+		{"Thanks", 3},
+	}
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %d: %+v", len(expected), len(tokens), tokens)
+	}
+	for i, tt := range expected {
+		if tokens[i].text != tt.text || tokens[i].segment != tt.segment {
+			t.Errorf("Token %d: expected %q in segment %d, got %q in segment %d",
+				i, tt.text, tt.segment, tokens[i].text, tokens[i].segment)
+		}
+	}
+
+	text := "Dear José García"
+	if got := text[tokens[1].start:tokens[1].end]; got != "José" {
+		t.Errorf("Expected byte offsets to cover %q, got %q", "José", got)
+	}
+}
+
+func TestScanText(t *testing.T) {
+	detector := New(createTestDataset())
+	text := "Please send the report to José García by Friday. John Smith, Maria Lopez and the team agreed."
+
+	matches := detector.ScanText(text, DefaultScanOptions())
+
+	expected := []string{"José García", "John Smith", "Maria Lopez"}
+	if len(matches) != len(expected) {
+		t.Fatalf("Expected %d matches, got %d: %+v", len(expected), len(matches), matches)
+	}
+	for i, match := range matches {
+		if match.Text != expected[i] {
+			t.Errorf("Match %d: expected %q, got %q", i, expected[i], match.Text)
+		}
+		if text[match.Start:match.End] != match.Text {
+			t.Errorf("Match %d: offsets [%d:%d] don't cover %q", i, match.Start, match.End, match.Text)
+		}
+		if !match.Result.IsLikelyName {
+			t.Errorf("Match %d: expected result to be a likely name", i)
+		}
+	}
+}
+
+func TestDetectNamesInText(t *testing.T) {
+	detector := New(createTestDataset())
+	text := "Minutes: Jose Garcia, John Smith. Action items for the team."
+
+	matches := detector.DetectNamesInText(text)
+	expected := detector.ScanText(text, DefaultScanOptions())
+	if len(matches) != 2 || len(matches) != len(expected) {
+		t.Fatalf("Expected 2 matches like ScanText, got %+v", matches)
+	}
+	for i := range matches {
+		if matches[i].Text != expected[i].Text || matches[i].Start != expected[i].Start {
+			t.Errorf("Match %d: expected %q at %d, got %q at %d", i, expected[i].Text, expected[i].Start, matches[i].Text, matches[i].Start)
+		}
+	}
+}
+
+func TestDetectPIIInText(t *testing.T) {
+	detector := New(createTestDataset())
+	text := "Chat with Maria Lopez about the refund"
+
+	if matches := detector.DetectPIIInText(text, 0.99); len(matches) != 0 {
+		t.Errorf("Expected no matches at threshold 0.99, got %+v", matches)
+	}
+
+	matches := detector.DetectPIIInText(text, 0.3)
+	if len(matches) != 1 || matches[0].Text != "Maria Lopez" {
+		t.Fatalf("Expected a single match for %q, got %+v", "Maria Lopez", matches)
+	}
+	if matches[0].Result.Decision.Threshold != 0.3 {
+		t.Errorf("Expected the match to be scored at threshold 0.3, got %v", matches[0].Result.Decision.Threshold)
+	}
+}
+
+func TestScanText_NoOverlap(t *testing.T) {
+	detector := New(createTestDataset())
+	text := "Jose Manuel Garcia Lopez"
+
+	matches := detector.ScanText(text, DefaultScanOptions())
+	if len(matches) != 1 || matches[0].Text != text {
+		t.Fatalf("Expected a single match covering the full name, got %+v", matches)
+	}
+}
+
+func TestScanText_Stride(t *testing.T) {
+	detector := New(createTestDataset())
+	text := "Report from John Smith"
+
+	opts := DefaultScanOptions()
+	opts.Stride = 2
+	opts.MaxWindow = 2
+	if matches := detector.ScanText(text, opts); len(matches) != 1 || matches[0].Text != "John Smith" {
+		t.Errorf("Expected stride 2 to find %q, got %+v", "John Smith", matches)
+	}
+
+	// "John Smith" starts at token 2, which stride 3 skips
+	opts.Stride = 3
+	if matches := detector.ScanText(text, opts); len(matches) != 0 {
+		t.Errorf("Expected stride 3 to skip the name, got %+v", matches)
+	}
+}
+
+// buildDocument creates a long document with a name every namesEvery sentences
+func buildDocument(sentences, namesEvery int) string {
+	var b strings.Builder
+	for i := 0; i < sentences; i++ {
+		if i%namesEvery == 0 {
+			b.WriteString("The quarterly report was reviewed by Jose Garcia and approved. ")
+		} else {
+			b.WriteString("The quarterly report was reviewed by the board and approved. ")
+		}
+	}
+	return b.String()
+}
+
+func BenchmarkScanText_FewNames(b *testing.B) {
+	detector := New(createTestDataset())
+	text := buildDocument(1000, 100)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		detector.ScanText(text, DefaultScanOptions())
+	}
+}
+
+func BenchmarkScanText_ManyNames(b *testing.B) {
+	detector := New(createTestDataset())
+	text := buildDocument(1000, 1)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		detector.ScanText(text, DefaultScanOptions())
+	}
+}
@@ -0,0 +1,147 @@
+package detector
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+// usernamePatternPrefix marks patterns of names recovered from usernames
+const usernamePatternPrefix = "username_"
+
+// detectUsername tries to recover a first name and surname from a single
+// username-like token such as "jose.garcia", "jose_garcia@example.com",
+// "josegarcia" or "jgarcia". It returns false when no name could be recovered,
+// and ErrCanceled once ctx is done. Every name it reports comes from input
+// position 0.
+func (d *Detector) detectUsername(ctx context.Context, token string, threshold float64, hints *DetectionHints) (types.PIIResult, bool, error) {
+	local := strings.ToLower(strings.TrimSpace(token))
+	if at := strings.Index(local, "@"); at >= 0 {
+		local = local[:at]
+	}
+	local = strings.TrimRightFunc(local, unicode.IsDigit)
+
+	parts := strings.FieldsFunc(local, func(r rune) bool {
+		return r == '.' || r == '_' || r == '-' || r == '+'
+	})
+
+	switch {
+	case len(parts) == 2 && len([]rune(parts[0])) == 1:
+		// "j.garcia": leading initial followed by a surname
+		result, ok := d.scoreInitialSurname(parts[0], parts[1], threshold)
+		return result, ok, nil
+	case len(parts) >= 2:
+		// "jose.garcia": separators already delimit the names
+		return d.detectUsernameParts(ctx, parts, threshold, hints)
+	case len(parts) == 1:
+		return d.splitConcatenatedUsername(ctx, parts[0], threshold)
+	}
+
+	return types.PIIResult{}, false, nil
+}
+
+// detectUsernameParts scores the separated parts of a username like any
+// other input words, within the same word limits
+func (d *Detector) detectUsernameParts(ctx context.Context, parts []string, threshold float64, hints *DetectionHints) (types.PIIResult, bool, error) {
+	if _, maxWords := d.config.wordLimits(); len(parts) > maxWords {
+		return types.PIIResult{}, false, nil
+	}
+
+	words, positions := d.cleanWords(parts)
+	for i := range positions {
+		positions[i] = []int{0}
+	}
+	words, positions, composed := d.composePrefixes(words, positions)
+	if len(words) < 2 {
+		return types.PIIResult{}, false, nil
+	}
+
+	result, err := d.detectWords(ctx, words, positions, composed, threshold, hints)
+	if err != nil || len(result.Details.FirstNames) == 0 {
+		return types.PIIResult{}, false, err
+	}
+	result.Details.Pattern = usernamePatternPrefix + result.Details.Pattern
+	return result, true, nil
+}
+
+// splitConcatenatedUsername recovers names from a token without separators,
+// trying every first name + surname split ("josegarcia") and the
+// first-initial + surname pattern ("jgarcia"), keeping the best score
+func (d *Detector) splitConcatenatedUsername(ctx context.Context, token string, threshold float64) (types.PIIResult, bool, error) {
+	runes := []rune(token)
+
+	var combinations []types.NameCombination
+	for i := 2; i <= len(runes)-2; i++ {
+		first, last := string(runes[:i]), string(runes[i:])
+		if _, exists := d.scorer.lookup(first, true); !exists {
+			continue
+		}
+		if _, exists := d.scorer.lookup(last, false); !exists {
+			continue
+		}
+		combinations = append(combinations, types.NameCombination{FirstNames: []string{first}, Surnames: []string{last}})
+	}
+
+	bestCombo, bestScore, err := d.findBestCombination(ctx, combinations)
+	if err != nil {
+		return types.PIIResult{}, false, err
+	}
+	bestScore = d.scorer.config.Calibration.Apply(bestScore)
+
+	var initialResult types.PIIResult
+	var hasInitial bool
+	if len(runes) > 2 {
+		initialResult, hasInitial = d.scoreInitialSurname(string(runes[:1]), string(runes[1:]), threshold)
+	}
+
+	if bestScore == 0 && !hasInitial {
+		return types.PIIResult{}, false, nil
+	}
+	if hasInitial && initialResult.Confidence > bestScore {
+		return initialResult, true, nil
+	}
+
+	result := d.buildResult(scoredResult{
+		combo:     bestCombo,
+		score:     bestScore,
+		factors:   d.scorer.Factors(bestCombo),
+		tokens:    append(append([]string{}, bestCombo.FirstNames...), bestCombo.Surnames...),
+		positions: [][]int{{0}, {0}},
+	}, threshold)
+	result.Details.Pattern = usernamePatternPrefix + result.Details.Pattern
+
+	return result, true, nil
+}
+
+// scoreInitialSurname scores a first-initial + surname username. The initial
+// carries no evidence of its own, so the confidence is the surname's match
+// score and the details are those of the surname alone.
+func (d *Detector) scoreInitialSurname(initial, surname string, threshold float64) (types.PIIResult, bool) {
+	score, matched := d.scorer.scoreNames([]string{surname}, false)
+	if len(matched) == 0 {
+		return types.PIIResult{}, false
+	}
+	if score > 1.0 {
+		score = 1.0
+	}
+
+	result := d.buildResult(scoredResult{
+		combo: types.NameCombination{Surnames: []string{surname}},
+		score: d.scorer.config.Calibration.Apply(score),
+		factors: []types.Factor{{
+			Name:   "name_matches",
+			Impact: score,
+			Detail: "surname matched after a first initial",
+		}},
+		tokens:    []string{surname},
+		positions: [][]int{{0}},
+	}, threshold)
+	result.Details.FirstNames, _ = d.caseNames([]string{initial}, nil)
+	result.Details.FirstNameIndices = []int{0}
+	result.Details.Pattern = usernamePatternPrefix + "initial_1_last"
+	result.Details.Order = orderWestern
+
+	return result, true
+}
@@ -0,0 +1,153 @@
+package detector
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+func TestDetectPII_Usernames(t *testing.T) {
+	dataset := createTestDataset()
+	config := DefaultDetectorConfig()
+	config.DetectUsernames = true
+	detector := NewWithDetectorConfig(dataset, DefaultScoreConfig(), config)
+
+	tests := []struct {
+		name          string
+		input         string
+		expectedFirst []string
+		expectedLast  []string
+		pattern       string
+	}{
+		{"Dotted local part", "jose.garcia", []string{"jose"}, []string{"garcia"}, "username_1_first_1_last"},
+		{"Email address", "jose_garcia@example.com", []string{"jose"}, []string{"garcia"}, "username_1_first_1_last"},
+		{"Trailing digits", "john.smith82", []string{"john"}, []string{"smith"}, "username_1_first_1_last"},
+		{"Concatenated names", "josegarcia", []string{"jose"}, []string{"garcia"}, "username_1_first_1_last"},
+		{"Initial and surname", "jgarcia", []string{"j"}, []string{"garcia"}, "username_initial_1_last"},
+		{"Dotted initial", "j.smith", []string{"j"}, []string{"smith"}, "username_initial_1_last"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := detector.DetectPIIWithThreshold([]string{tt.input}, 0.5)
+
+			if result.Details.Pattern != tt.pattern {
+				t.Errorf("Expected pattern %q, got %q", tt.pattern, result.Details.Pattern)
+			}
+			if !equalStringSlices(result.Details.FirstNames, tt.expectedFirst) {
+				t.Errorf("Expected first names %v, got %v", tt.expectedFirst, result.Details.FirstNames)
+			}
+			if !equalStringSlices(result.Details.Surnames, tt.expectedLast) {
+				t.Errorf("Expected surnames %v, got %v", tt.expectedLast, result.Details.Surnames)
+			}
+			if !result.IsLikelyName {
+				t.Errorf("Expected %q to be detected (confidence: %.3f)", tt.input, result.Confidence)
+			}
+			// Both parts come from the single input word
+			if !equalIntSlices(result.Details.FirstNameIndices, []int{0}) || !equalIntSlices(result.Details.SurnameIndices, []int{0}) {
+				t.Errorf("Expected indices [0] and [0], got %v and %v", result.Details.FirstNameIndices, result.Details.SurnameIndices)
+			}
+		})
+	}
+
+	// Tokens that don't contain names fall back to the regular length check
+	if result := detector.DetectPII([]string{"support.team"}); result.Details.Pattern != "invalid_length" {
+		t.Errorf("Expected invalid_length for non-name username, got %q", result.Details.Pattern)
+	}
+
+	// The mode is off by default
+	if result := New(dataset).DetectPII([]string{"jose.garcia"}); result.Details.Pattern != "invalid_length" {
+		t.Errorf("Expected invalid_length with username detection disabled, got %q", result.Details.Pattern)
+	}
+}
+
+// Usernames are described like the same names given as separate words
+func TestDetectPII_UsernameDetails(t *testing.T) {
+	dataset := createTestDataset()
+	config := DefaultDetectorConfig()
+	config.DetectUsernames = true
+	config.NormalizeOutput = true
+	config.ReportAmbiguousTokens = true
+	detector := NewWithDetectorConfig(dataset, DefaultScoreConfig(), config)
+
+	tests := []struct {
+		input string
+		words []string
+	}{
+		{"jose.garcia", []string{"jose", "garcia"}},
+		{"josegarcia", []string{"jose", "garcia"}},
+		{"jose.manuel.garcia", []string{"jose", "manuel", "garcia"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := detector.DetectPIIWithThreshold([]string{tt.input}, 0.5)
+			expected := detector.DetectPIIWithThreshold(tt.words, 0.5)
+
+			if result.Details.Pattern != usernamePatternPrefix+expected.Details.Pattern {
+				t.Errorf("Expected pattern %q, got %q", usernamePatternPrefix+expected.Details.Pattern, result.Details.Pattern)
+			}
+			if !equalIntSlices(result.Details.FirstNameIndices, []int{0}) || !equalIntSlices(result.Details.SurnameIndices, []int{0}) {
+				t.Errorf("Expected indices [0] and [0], got %v and %v", result.Details.FirstNameIndices, result.Details.SurnameIndices)
+			}
+
+			result.Details.Pattern = expected.Details.Pattern
+			result.Details.FirstNameIndices = expected.Details.FirstNameIndices
+			result.Details.SurnameIndices = expected.Details.SurnameIndices
+			if !reflect.DeepEqual(result.Details, expected.Details) {
+				t.Errorf("Expected details %+v, got %+v", expected.Details, result.Details)
+			}
+			if result.Confidence != expected.Confidence || result.Decision.Reason != expected.Decision.Reason {
+				t.Errorf("Expected confidence %.3f (%s), got %.3f (%s)",
+					expected.Confidence, expected.Decision.Reason, result.Confidence, result.Decision.Reason)
+			}
+		})
+	}
+
+	// An initial is described by its surname alone
+	result := detector.DetectPIIWithThreshold([]string{"jgarcia"}, 0.5)
+	if result.Details.RoleFit != 1.0 || len(result.Details.MatchedSurnames) != 1 || result.Details.TopCountry == "" {
+		t.Errorf("Expected surname details for jgarcia, got %+v", result.Details)
+	}
+}
+
+// Names split from a username report the username's input position
+func TestDetectPII_UsernameIndices(t *testing.T) {
+	dataset := createTestDataset()
+	dataset.LastNames["MCDONALD"] = &types.NameData{
+		Country: map[string]float32{"US": 1.0},
+		Rank:    map[string]int32{"US": 50},
+	}
+	config := DefaultDetectorConfig()
+	config.DetectUsernames = true
+	detector := NewWithDetectorConfig(dataset, DefaultScoreConfig(), config)
+
+	tests := []struct {
+		name           string
+		words          []string
+		firstIndices   []int
+		surnameIndices []int
+	}{
+		{"Dotted username", []string{"jose.manuel.garcia"}, []int{0}, []int{0}},
+		{"Composed surname", []string{"john.mc.donald"}, []int{0}, []int{0}},
+		{"Concatenated username", []string{"josegarcia"}, []int{0}, []int{0}},
+		{"Initial", []string{"jgarcia"}, []int{0}, []int{0}},
+		{"Separate words", []string{"", "Jose", "Garcia"}, []int{1}, []int{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := detector.DetectPIIWithThreshold(tt.words, 0.5)
+			if !result.Analyzed || len(result.Details.FirstNames) == 0 {
+				t.Fatalf("Expected %v to be split into names, got pattern %q", tt.words, result.Details.Pattern)
+			}
+			if !equalIntSlices(result.Details.FirstNameIndices, tt.firstIndices) {
+				t.Errorf("Expected first name indices %v, got %v", tt.firstIndices, result.Details.FirstNameIndices)
+			}
+			if !equalIntSlices(result.Details.SurnameIndices, tt.surnameIndices) {
+				t.Errorf("Expected surname indices %v, got %v", tt.surnameIndices, result.Details.SurnameIndices)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// Package grpcserver implements the names.Detector gRPC service defined in
+// proto/detector.proto on top of a detector.Detector.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/montevive/go-name-detector/pkg/detector"
+	pb "github.com/montevive/go-name-detector/pkg/proto"
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+// Server serves Detect and DetectBatch from a single detector. It is safe for
+// concurrent use, as the detector is.
+type Server struct {
+	pb.UnimplementedDetectorServer
+
+	detector  *detector.Detector
+	threshold float64
+}
+
+// New returns a Server scoring with d. threshold is used for requests that
+// don't set their own.
+func New(d *detector.Detector, threshold float64) *Server {
+	return &Server{detector: d, threshold: threshold}
+}
+
+// Detect scores the words of a single request
+func (s *Server) Detect(ctx context.Context, req *pb.DetectRequest) (*pb.DetectResponse, error) {
+	return s.detect(req)
+}
+
+// DetectBatch scores each request as it is received and sends its response
+// before reading the next, so responses arrive in request order. The stream
+// ends with an InvalidArgument error at the first invalid request.
+func (s *Server) DetectBatch(stream pb.Detector_DetectBatchServer) error {
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resp, err := s.detect(req)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// detect validates req and scores it, applying the default threshold when
+// the request has none
+func (s *Server) detect(req *pb.DetectRequest) (*pb.DetectResponse, error) {
+	if len(req.GetWords()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "words are required")
+	}
+
+	threshold := s.threshold
+	if req.Threshold != nil {
+		threshold = req.GetThreshold()
+		if threshold < 0 || threshold > 1 {
+			return nil, status.Error(codes.InvalidArgument, "threshold must be between 0 and 1")
+		}
+	}
+
+	result := s.detector.DetectPIIWithThreshold(req.GetWords(), threshold)
+	return toResponse(req.GetId(), result), nil
+}
+
+// toResponse converts a detection result to its protobuf form
+func toResponse(id string, result types.PIIResult) *pb.DetectResponse {
+	details := result.Details
+	resp := &pb.DetectResponse{
+		Id:           id,
+		IsLikelyName: result.IsLikelyName,
+		Confidence:   result.Confidence,
+		Analyzed:     result.Analyzed,
+		Details: &pb.NameDetails{
+			FirstNames:          details.FirstNames,
+			Surnames:            details.Surnames,
+			Pattern:             details.Pattern,
+			Order:               details.Order,
+			TopCountry:          details.TopCountry,
+			TopCountryName:      details.TopCountryName,
+			Gender:              details.Gender,
+			GenderConfidence:    details.GenderConfidence,
+			MatchedFirstNames:   details.MatchedFirstNames,
+			MatchedSurnames:     details.MatchedSurnames,
+			FirstNameConfidence: details.FirstNameConfidence,
+			SurnameConfidence:   details.SurnameConfidence,
+			RareTokens:          details.RareTokens,
+			UnknownTokens:       details.UnknownTokens,
+		},
+		Decision: &pb.Decision{
+			Threshold:  result.Decision.Threshold,
+			Passed:     result.Decision.Passed,
+			Reason:     result.Decision.Reason,
+			Supporting: toFactors(result.Decision.Supporting),
+			Detracting: toFactors(result.Decision.Detracting),
+		},
+	}
+
+	for _, c := range details.TopCountries {
+		resp.Details.TopCountries = append(resp.Details.TopCountries, &pb.CountryScore{Country: c.Country, Score: c.Score})
+	}
+	return resp
+}
+
+// toFactors converts decision factors to their protobuf form
+func toFactors(factors []types.Factor) []*pb.Factor {
+	if len(factors) == 0 {
+		return nil
+	}
+	out := make([]*pb.Factor, len(factors))
+	for i, f := range factors {
+		out[i] = &pb.Factor{Name: f.Name, Impact: f.Impact, Detail: f.Detail}
+	}
+	return out
+}
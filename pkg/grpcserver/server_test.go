@@ -0,0 +1,195 @@
+package grpcserver
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	protobuf "google.golang.org/protobuf/proto"
+
+	"github.com/montevive/go-name-detector/pkg/detector"
+	pb "github.com/montevive/go-name-detector/pkg/proto"
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+func createTestDataset() *types.NameDataset {
+	return &types.NameDataset{
+		FirstNames: map[string]*types.NameData{
+			"JOSE": {
+				Country: map[string]float32{"ES": 0.159, "MX": 0.203, "US": 0.098},
+				Gender:  map[string]float32{"M": 0.98, "F": 0.02},
+				Rank:    map[string]int32{"ES": 1, "MX": 2, "US": 15},
+			},
+			"JOHN": {
+				Country: map[string]float32{"US": 0.456, "GB": 0.234, "CA": 0.123},
+				Gender:  map[string]float32{"M": 0.99, "F": 0.01},
+				Rank:    map[string]int32{"US": 8, "GB": 12, "CA": 15},
+			},
+		},
+		LastNames: map[string]*types.NameData{
+			"GARCIA": {
+				Country: map[string]float32{"ES": 0.11, "MX": 0.234, "US": 0.156},
+				Gender:  map[string]float32{},
+				Rank:    map[string]int32{"ES": 1, "MX": 3, "US": 6},
+			},
+			"SMITH": {
+				Country: map[string]float32{"US": 0.456, "GB": 0.234, "CA": 0.123},
+				Gender:  map[string]float32{},
+				Rank:    map[string]int32{"US": 1, "GB": 5, "CA": 3},
+			},
+		},
+	}
+}
+
+// newTestClient serves a Server over an in-memory listener and returns a
+// client connected to it
+func newTestClient(t *testing.T, d *detector.Detector) pb.DetectorClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	pb.RegisterDetectorServer(s, New(d, 0.7))
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewDetectorClient(conn)
+}
+
+func TestDetect(t *testing.T) {
+	d := detector.New(createTestDataset())
+	client := newTestClient(t, d)
+
+	resp, err := client.Detect(context.Background(), &pb.DetectRequest{Words: []string{"Jose", "Garcia"}, Id: "row-1"})
+	if err != nil {
+		t.Fatalf("Detect failed: %v", err)
+	}
+
+	expected := d.DetectPIIWithThreshold([]string{"Jose", "Garcia"}, 0.7)
+	if resp.GetId() != "row-1" {
+		t.Errorf("Expected id row-1, got %q", resp.GetId())
+	}
+	if resp.GetIsLikelyName() != expected.IsLikelyName || resp.GetConfidence() != expected.Confidence {
+		t.Errorf("Expected %v (%.3f), got %v (%.3f)",
+			expected.IsLikelyName, expected.Confidence, resp.GetIsLikelyName(), resp.GetConfidence())
+	}
+	if resp.GetDetails().GetTopCountry() != expected.Details.TopCountry {
+		t.Errorf("Expected top country %q, got %q", expected.Details.TopCountry, resp.GetDetails().GetTopCountry())
+	}
+	if resp.GetDecision().GetThreshold() != 0.7 {
+		t.Errorf("Expected the default threshold 0.7, got %v", resp.GetDecision().GetThreshold())
+	}
+	if resp.GetDecision().GetReason() == "" {
+		t.Error("Expected a decision reason")
+	}
+}
+
+func TestDetect_Threshold(t *testing.T) {
+	client := newTestClient(t, detector.New(createTestDataset()))
+
+	resp, err := client.Detect(context.Background(), &pb.DetectRequest{
+		Words:     []string{"Jose", "Garcia"},
+		Threshold: protobuf.Float64(0.99),
+	})
+	if err != nil {
+		t.Fatalf("Detect failed: %v", err)
+	}
+	if resp.GetDecision().GetThreshold() != 0.99 {
+		t.Errorf("Expected threshold 0.99, got %v", resp.GetDecision().GetThreshold())
+	}
+}
+
+func TestDetect_InvalidArgument(t *testing.T) {
+	client := newTestClient(t, detector.New(createTestDataset()))
+
+	tests := []struct {
+		name string
+		req  *pb.DetectRequest
+	}{
+		{"no words", &pb.DetectRequest{}},
+		{"threshold above 1", &pb.DetectRequest{Words: []string{"Jose", "Garcia"}, Threshold: protobuf.Float64(1.5)}},
+		{"negative threshold", &pb.DetectRequest{Words: []string{"Jose", "Garcia"}, Threshold: protobuf.Float64(-0.1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Detect(context.Background(), tt.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("Expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDetectBatch(t *testing.T) {
+	d := detector.New(createTestDataset())
+	client := newTestClient(t, d)
+
+	inputs := [][]string{
+		{"John", "Smith"},
+		{"The", "Quick", "Fox"},
+		{"Jose", "Garcia"},
+	}
+
+	stream, err := client.DetectBatch(context.Background())
+	if err != nil {
+		t.Fatalf("DetectBatch failed: %v", err)
+	}
+	for i, words := range inputs {
+		if err := stream.Send(&pb.DetectRequest{Words: words, Id: string(rune('a' + i))}); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend failed: %v", err)
+	}
+
+	for i, words := range inputs {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv %d failed: %v", i, err)
+		}
+		expected := d.DetectPIIWithThreshold(words, 0.7)
+		if resp.GetId() != string(rune('a'+i)) {
+			t.Errorf("Response %d: expected id %q, got %q", i, string(rune('a'+i)), resp.GetId())
+		}
+		if resp.GetIsLikelyName() != expected.IsLikelyName || resp.GetConfidence() != expected.Confidence {
+			t.Errorf("Response %d %v: expected %v (%.3f), got %v (%.3f)", i, words,
+				expected.IsLikelyName, expected.Confidence, resp.GetIsLikelyName(), resp.GetConfidence())
+		}
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("Expected io.EOF after the last response, got %v", err)
+	}
+}
+
+func TestDetectBatch_InvalidRequestEndsStream(t *testing.T) {
+	client := newTestClient(t, detector.New(createTestDataset()))
+
+	stream, err := client.DetectBatch(context.Background())
+	if err != nil {
+		t.Fatalf("DetectBatch failed: %v", err)
+	}
+	if err := stream.Send(&pb.DetectRequest{}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+}
@@ -0,0 +1,256 @@
+package loader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/klauspost/compress/zstd"
+	names "github.com/montevive/go-name-detector/pkg/proto"
+	"github.com/montevive/go-name-detector/pkg/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrUnknownFormat is returned by LoadAuto and LoadAutoSeparate for a file
+// that is not protobuf, JSON or CSV, compressed or not
+var ErrUnknownFormat = errors.New("unrecognized dataset format")
+
+// Dataset file formats recognized by LoadAuto
+const (
+	formatProtobuf = "protobuf"
+	formatJSON     = "json"
+	formatCSV      = "csv"
+)
+
+// LoadAuto loads a combined dataset of first names and surnames without the
+// caller naming its format: a protobuf file as written by WriteToFile or a
+// JSON file as written by ExportJSON. Gzip and zstd compression are detected
+// from their magic bytes. The format is taken from the extension left after
+// any ".gz" or ".zst" (".pb", ".json"), or else sniffed from the content.
+// CSV files and single-role protobuf files (see LoadSeparateFiles) are
+// rejected with a pointer to LoadAutoSeparate, and other files with
+// ErrUnknownFormat.
+func (l *Loader) LoadAuto(filename string) error {
+	if l.loaded {
+		return nil // Already loaded
+	}
+
+	data, format, err := readAuto(filename, true)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case formatJSON:
+		if err := l.LoadFromJSON(bytes.NewReader(data)); err != nil {
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+		return nil
+	case formatCSV:
+		return fmt.Errorf("%s: CSV name files hold either first names or surnames; load them with LoadAutoSeparate", filename)
+	}
+
+	var pbDataset names.CombinedNameDataset
+	err = proto.Unmarshal(data, &pbDataset)
+	if (err != nil || isEmptyCombined(&pbDataset)) && isSingleRole(data) {
+		return fmt.Errorf("%s: protobuf file holds a single list of names; load first names and surnames with LoadAutoSeparate", filename)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal protobuf: %w", err)
+	}
+
+	l.convertToInternalFormat(&pbDataset)
+	l.loaded = true
+
+	return nil
+}
+
+// LoadAutoSeparate loads first names and surnames from one file each, every
+// file being either a CSV file with the default columns (see LoadFromCSV) or
+// a single-role protobuf file (see LoadSeparateFiles), compressed or not. The
+// two files need not share a format. Formats are detected as in LoadAuto.
+func (l *Loader) LoadAutoSeparate(firstNamesFile, lastNamesFile string) error {
+	if l.loaded {
+		return nil // Already loaded
+	}
+
+	// Load both files before touching the dataset, so that a failure leaves
+	// it as it was and the load can be retried
+	firstNames, err := loadAutoRole(firstNamesFile)
+	if err != nil {
+		return fmt.Errorf("failed to load first names: %w", err)
+	}
+
+	lastNames, err := loadAutoRole(lastNamesFile)
+	if err != nil {
+		return fmt.Errorf("failed to load last names: %w", err)
+	}
+
+	maps.Copy(l.dataset.FirstNames, firstNames)
+	maps.Copy(l.dataset.LastNames, lastNames)
+	l.loaded = true
+	return nil
+}
+
+// loadAutoRole loads the names of a single-role CSV or protobuf file
+func loadAutoRole(filename string) (map[string]*types.NameData, error) {
+	data, format, err := readAuto(filename, false)
+	if err != nil {
+		return nil, err
+	}
+
+	targetMap := make(map[string]*types.NameData)
+	switch format {
+	case formatCSV:
+		if err := parseCSV(filename, data, DefaultCSVColumns(), targetMap, nil, false); err != nil {
+			return nil, err
+		}
+		return targetMap, nil
+	case formatJSON:
+		return nil, fmt.Errorf("%s: JSON datasets hold both roles; load them with LoadAuto", filename)
+	}
+
+	var pbDataset names.NameDataset
+	if err := proto.Unmarshal(data, &pbDataset); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal protobuf: %w", err)
+	}
+	indexEntries(pbDataset.Entries, targetMap)
+
+	return targetMap, nil
+}
+
+// readAuto reads and decompresses a dataset file and detects its format.
+// combined selects which protobuf message content sniffing looks for.
+func readAuto(filename string, combined bool) ([]byte, string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	data, err := decompressAuto(file)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+
+	format := formatFromExtension(filename)
+	if format == "" {
+		format = sniffFormat(data, combined)
+	}
+	if format == "" {
+		return nil, "", fmt.Errorf("%w: %s", ErrUnknownFormat, filename)
+	}
+
+	return data, format, nil
+}
+
+// decompressAuto reads all of r, decompressing gzip or zstd data detected
+// from its magic bytes
+func decompressAuto(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b:
+		// Gzip magic bytes: 0x1f, 0x8b
+		gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		defer gzipReader.Close()
+		return io.ReadAll(gzipReader)
+	case bytes.HasPrefix(data, zstdMagic):
+		zstdReader, err := zstd.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd reader: %w", err)
+		}
+		defer zstdReader.Close()
+		return io.ReadAll(zstdReader)
+	}
+
+	return data, nil
+}
+
+// formatFromExtension returns the format named by the extension of filename
+// after any compression extension, or an empty string when it names none
+func formatFromExtension(filename string) string {
+	name := strings.TrimSuffix(strings.TrimSuffix(strings.ToLower(filename), ".gz"), ".zst")
+	switch filepath.Ext(name) {
+	case ".pb":
+		return formatProtobuf
+	case ".json":
+		return formatJSON
+	case ".csv":
+		return formatCSV
+	}
+	return ""
+}
+
+// sniffFormat guesses the format of decompressed data: JSON when it is a
+// valid JSON object, CSV when its first line is text with a comma, and
+// protobuf when it parses as a non-empty dataset message. It returns an
+// empty string when none fits.
+func sniffFormat(data []byte, combined bool) string {
+	text := bytes.TrimLeftFunc(bytes.TrimPrefix(data, utf8BOM), unicode.IsSpace)
+	if bytes.HasPrefix(text, []byte("{")) && json.Valid(text) {
+		return formatJSON
+	}
+
+	firstLine, _, _ := bytes.Cut(text, []byte("\n"))
+	if isTextLine(firstLine) && bytes.ContainsRune(firstLine, ',') {
+		return formatCSV
+	}
+
+	if combined {
+		// A single-role file is recognized too, so that LoadAuto can point
+		// it to LoadAutoSeparate
+		var pbDataset names.CombinedNameDataset
+		if proto.Unmarshal(data, &pbDataset) == nil && !isEmptyCombined(&pbDataset) {
+			return formatProtobuf
+		}
+	}
+	if isSingleRole(data) {
+		return formatProtobuf
+	}
+
+	return ""
+}
+
+// isEmptyCombined reports whether a combined dataset has no entries in
+// either role
+func isEmptyCombined(pbDataset *names.CombinedNameDataset) bool {
+	return len(pbDataset.GetFirstNames().GetEntries())+len(pbDataset.GetLastNames().GetEntries()) == 0
+}
+
+// isSingleRole reports whether data parses as a non-empty single-role
+// dataset message
+func isSingleRole(data []byte) bool {
+	var pbDataset names.NameDataset
+	return proto.Unmarshal(data, &pbDataset) == nil && len(pbDataset.Entries) > 0
+}
+
+// isTextLine reports whether line is valid UTF-8 without control characters
+// other than tabs and a trailing carriage return
+func isTextLine(line []byte) bool {
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	if !utf8.Valid(line) {
+		return false
+	}
+	for _, r := range string(line) {
+		if unicode.IsControl(r) && r != '\t' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,281 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+// utf8BOM is the byte order mark some spreadsheet tools write at the start
+// of UTF-8 CSV files
+var utf8BOM = []byte("\xEF\xBB\xBF")
+
+// CSVColumns maps NameData fields to the header names of a CSV file. Header
+// matching is case-insensitive. Only Name is required; a field whose column is
+// missing from the header is left empty.
+type CSVColumns struct {
+	Name        string // Name column
+	Country     string // Country code column
+	Gender      string // Gender column ("M"/"F")
+	Rank        string // Rank of the name within the row's country (1 = most popular)
+	Probability string // Probability of the row's country for the name
+}
+
+// DefaultCSVColumns returns the column mapping for name,country,gender,rank
+// files with an optional probability column
+func DefaultCSVColumns() CSVColumns {
+	return CSVColumns{
+		Name:        "name",
+		Country:     "country",
+		Gender:      "gender",
+		Rank:        "rank",
+		Probability: "probability",
+	}
+}
+
+// LoadFromCSV loads first and last names from CSV files with a header row
+// using the default column mapping
+func (l *Loader) LoadFromCSV(firstNamesPath, lastNamesPath string) error {
+	return l.LoadFromCSVWithColumns(firstNamesPath, lastNamesPath, DefaultCSVColumns())
+}
+
+// LoadFromCSVWithColumns loads first and last names from CSV files whose
+// header names the columns given in columns. Rows for the same name are
+// merged into one entry with a rank and country probability per country.
+// Without a probability column, a name's countries share the probability
+// evenly; gender probabilities are the share of the name's rows with each
+// gender. Files ending in .gz or .zst are decompressed transparently, and a
+// leading UTF-8 byte order mark is ignored. A malformed rank fails the load
+// with an error naming the file and line.
+func (l *Loader) LoadFromCSVWithColumns(firstNamesPath, lastNamesPath string, columns CSVColumns) error {
+	if l.loaded {
+		return nil // Already loaded
+	}
+
+	if err := loadCSV(firstNamesPath, columns, l.dataset.FirstNames); err != nil {
+		return fmt.Errorf("failed to load first names: %w", err)
+	}
+
+	if err := loadCSV(lastNamesPath, columns, l.dataset.LastNames); err != nil {
+		return fmt.Errorf("failed to load last names: %w", err)
+	}
+
+	l.loaded = true
+	return nil
+}
+
+// LoadFirstNamesCSV adds the first names of a CSV stream with a header row
+// to the dataset using the default column mapping
+func (l *Loader) LoadFirstNamesCSV(r io.Reader) error {
+	return l.LoadFirstNamesCSVWithColumns(r, DefaultCSVColumns())
+}
+
+// LoadLastNamesCSV adds the surnames of a CSV stream with a header row to the
+// dataset using the default column mapping
+func (l *Loader) LoadLastNamesCSV(r io.Reader) error {
+	return l.LoadLastNamesCSVWithColumns(r, DefaultCSVColumns())
+}
+
+// LoadFirstNamesCSVWithColumns adds the first names of a CSV stream whose
+// header names the columns given in columns. Rows are aggregated as in
+// LoadFromCSVWithColumns, except that rows with a malformed rank are skipped
+// rather than failing the load, as public name lists often have a few. Unlike
+// the file loaders it may be called on a dataset that is already loaded.
+func (l *Loader) LoadFirstNamesCSVWithColumns(r io.Reader, columns CSVColumns) error {
+	if err := loadCSVReader("first names", r, columns, l.dataset.FirstNames, l.dataset.FirstNameListSizes); err != nil {
+		return fmt.Errorf("failed to load first names: %w", err)
+	}
+	l.loaded = true
+	return nil
+}
+
+// LoadLastNamesCSVWithColumns adds the surnames of a CSV stream whose header
+// names the columns given in columns, skipping rows with a malformed rank
+func (l *Loader) LoadLastNamesCSVWithColumns(r io.Reader, columns CSVColumns) error {
+	if err := loadCSVReader("last names", r, columns, l.dataset.LastNames, l.dataset.LastNameListSizes); err != nil {
+		return fmt.Errorf("failed to load last names: %w", err)
+	}
+	l.loaded = true
+	return nil
+}
+
+// loadCSV parses a CSV name file and merges its rows into targetMap
+func loadCSV(filename string, columns CSVColumns, targetMap map[string]*types.NameData) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	data, err := readCompressed(filename, file)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+
+	return parseCSV(filename, data, columns, targetMap, nil, false)
+}
+
+// loadCSVReader parses an uncompressed CSV stream and merges its rows into
+// targetMap, growing its list sizes when they were computed and skipping rows
+// with a malformed rank. source names the stream in error messages.
+func loadCSVReader(source string, r io.Reader, columns CSVColumns, targetMap map[string]*types.NameData, sizes map[string]int32) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", source, err)
+	}
+
+	return parseCSV(source, data, columns, targetMap, sizes, true)
+}
+
+// parseCSV merges the rows of CSV data into targetMap. A (name, country)
+// pair seen on several rows keeps its best (lowest) rank. Stored ranks grow
+// sizes as in growListSizes. A malformed rank is an error naming source and
+// the line, or skips the row when skipBadRanks is set.
+func parseCSV(source string, data []byte, columns CSVColumns, targetMap map[string]*types.NameData, sizes map[string]int32, skipBadRanks bool) error {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return fmt.Errorf("%s: missing header row", source)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+
+	positions := make(map[string]int, len(header))
+	for i, column := range header {
+		positions[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	column := func(name string) int {
+		if i, exists := positions[strings.ToLower(name)]; exists && name != "" {
+			return i
+		}
+		return -1
+	}
+
+	nameCol := column(columns.Name)
+	if nameCol < 0 {
+		return fmt.Errorf("%s: header has no %q column", source, columns.Name)
+	}
+	countryCol := column(columns.Country)
+	genderCol := column(columns.Gender)
+	rankCol := column(columns.Rank)
+	probabilityCol := column(columns.Probability)
+
+	var loaded []*types.NameData
+	genderCounts := make(map[*types.NameData]map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		name := field(nameCol)
+		if name == "" {
+			continue
+		}
+
+		var rank int64
+		if value := field(rankCol); value != "" {
+			rank, err = strconv.ParseInt(value, 10, 32)
+			if err == nil && rank < 0 {
+				err = errors.New("rank must not be negative")
+			}
+			if err != nil {
+				if skipBadRanks {
+					continue
+				}
+				return fmt.Errorf("%s:%d: invalid rank %q for %s: %w", source, line, value, name, err)
+			}
+		}
+
+		var probability float64
+		if value := field(probabilityCol); value != "" {
+			probability, err = strconv.ParseFloat(value, 32)
+			if err != nil {
+				return fmt.Errorf("%s:%d: invalid probability %q for %s: %w", source, line, value, name, err)
+			}
+		}
+
+		key := normalizeKey(name)
+		nameData, exists := targetMap[key]
+		if !exists {
+			nameData = &types.NameData{Name: name}
+			targetMap[key] = nameData
+			loaded = append(loaded, nameData)
+		}
+		if nameData.Country == nil {
+			nameData.Country = make(map[string]float32)
+		}
+		if nameData.Gender == nil {
+			nameData.Gender = make(map[string]float32)
+		}
+		if nameData.Rank == nil {
+			nameData.Rank = make(map[string]int32)
+		}
+
+		if country := strings.ToUpper(field(countryCol)); country != "" {
+			// Keep the best rank, and its probability, when a country repeats
+			best, ranked := nameData.Rank[country]
+			_, seen := nameData.Country[country]
+			if !seen || (rank > 0 && (!ranked || int32(rank) < best)) {
+				nameData.Country[country] = float32(probability)
+				if rank > 0 {
+					nameData.Rank[country] = int32(rank)
+					if sizes != nil && int32(rank) > sizes[country] {
+						sizes[country] = int32(rank)
+					}
+					if nameData.MinRank == 0 || int32(rank) < nameData.MinRank {
+						nameData.MinRank = int32(rank)
+					}
+				}
+			}
+		}
+
+		if gender := strings.ToUpper(field(genderCol)); gender != "" {
+			if genderCounts[nameData] == nil {
+				genderCounts[nameData] = make(map[string]int)
+			}
+			genderCounts[nameData][gender]++
+		}
+	}
+
+	// Without a probability column, split each name evenly across its countries
+	if probabilityCol < 0 {
+		for _, nameData := range loaded {
+			for country := range nameData.Country {
+				nameData.Country[country] = 1 / float32(len(nameData.Country))
+			}
+		}
+	}
+	for nameData, counts := range genderCounts {
+		var total int
+		for _, count := range counts {
+			total += count
+		}
+		for gender, count := range counts {
+			nameData.Gender[gender] = float32(count) / float32(total)
+		}
+	}
+
+	return nil
+}
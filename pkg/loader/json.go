@@ -0,0 +1,147 @@
+package loader
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sort"
+
+	names "github.com/montevive/go-name-detector/pkg/proto"
+	"github.com/montevive/go-name-detector/pkg/types"
+)
+
+// jsonDataset is the JSON form of a NameDataset. Names are keyed by their
+// source spelling rather than the uppercased lookup key.
+type jsonDataset struct {
+	FirstNames map[string]jsonNameData `json:"first_names"`
+	LastNames  map[string]jsonNameData `json:"last_names"`
+}
+
+// jsonNameData is the JSON form of a single NameData entry
+type jsonNameData struct {
+	Country map[string]float32 `json:"country,omitempty"`
+	Gender  map[string]float32 `json:"gender,omitempty"`
+	Rank    map[string]int32   `json:"rank,omitempty"`
+	Aliases []string           `json:"aliases,omitempty"`
+}
+
+// LoadFromJSON loads a dataset in the format written by ExportJSON.
+// Entries and their aliases are indexed exactly like the protobuf dataset.
+func (l *Loader) LoadFromJSON(r io.Reader) error {
+	if l.loaded {
+		return nil // Already loaded
+	}
+
+	var dataset jsonDataset
+	if err := json.NewDecoder(r).Decode(&dataset); err != nil {
+		return fmt.Errorf("failed to decode JSON dataset: %w", err)
+	}
+
+	indexEntries(jsonEntries(dataset.FirstNames), l.dataset.FirstNames)
+	indexEntries(jsonEntries(dataset.LastNames), l.dataset.LastNames)
+	l.computeListSizes()
+
+	l.loaded = true
+	return nil
+}
+
+// ExportJSON writes the loaded dataset as indented JSON keyed by each name's
+// source spelling. Alias keys are not written separately; they are listed
+// under their entry's "aliases". Entries are written one at a time in name
+// order, so the full dataset is never held in memory as JSON and exports of
+// two dataset versions can be diffed.
+func (l *Loader) ExportJSON(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString("{\n")
+	if err := writeJSONSection(bw, "first_names", l.dataset.FirstNames); err != nil {
+		return err
+	}
+	bw.WriteString(",\n")
+	if err := writeJSONSection(bw, "last_names", l.dataset.LastNames); err != nil {
+		return err
+	}
+	bw.WriteString("\n}\n")
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write JSON dataset: %w", err)
+	}
+	return nil
+}
+
+// writeJSONSection writes the entries of targetMap as the JSON object field
+// key, indented to match ExportJSON
+func writeJSONSection(w *bufio.Writer, key string, targetMap map[string]*types.NameData) error {
+	entries := exportEntries(targetMap)
+	names := slices.Sorted(maps.Keys(entries))
+
+	fmt.Fprintf(w, "  %q: {", key)
+	for i, name := range names {
+		data := entries[name]
+		encodedName, err := json.Marshal(name)
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON dataset: %w", err)
+		}
+		encodedData, err := json.MarshalIndent(jsonNameData{
+			Country: data.Country,
+			Gender:  data.Gender,
+			Rank:    data.Rank,
+			Aliases: data.Aliases,
+		}, "    ", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON dataset: %w", err)
+		}
+
+		if i > 0 {
+			w.WriteString(",")
+		}
+		w.WriteString("\n    ")
+		w.Write(encodedName)
+		w.WriteString(": ")
+		w.Write(encodedData)
+	}
+	if len(names) > 0 {
+		w.WriteString("\n  ")
+	}
+	w.WriteString("}")
+	return nil
+}
+
+// jsonEntries converts decoded JSON entries to protobuf entries sorted by
+// name, so alias collisions resolve the same way on every load
+func jsonEntries(entries map[string]jsonNameData) []*names.NameEntry {
+	converted := make([]*names.NameEntry, 0, len(entries))
+	for name, data := range entries {
+		converted = append(converted, &names.NameEntry{
+			Name:    name,
+			Country: data.Country,
+			Gender:  data.Gender,
+			Rank:    data.Rank,
+			Aliases: data.Aliases,
+		})
+	}
+	sort.Slice(converted, func(i, j int) bool {
+		return converted[i].Name < converted[j].Name
+	})
+	return converted
+}
+
+// exportEntries returns the indexed entries keyed by source spelling,
+// skipping keys that only exist as aliases of another entry
+func exportEntries(targetMap map[string]*types.NameData) map[string]*types.NameData {
+	exported := make(map[string]*types.NameData, len(targetMap))
+	for key, data := range targetMap {
+		name := data.Name
+		if name == "" {
+			name = key
+		} else if canonical := normalizeKey(name); canonical != key && targetMap[canonical] == data {
+			continue // Alias key
+		}
+
+		exported[name] = data
+	}
+	return exported
+}
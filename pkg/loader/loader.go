@@ -1,13 +1,20 @@
 package loader
 
 import (
+	"bufio"
 	"bytes"
 	"compress/gzip"
 	"fmt"
 	"io"
+	"io/fs"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
+	"github.com/klauspost/compress/zstd"
 	names "github.com/montevive/go-name-detector/pkg/proto"
 	"github.com/montevive/go-name-detector/pkg/types"
 	"google.golang.org/protobuf/proto"
@@ -17,6 +24,7 @@ import (
 type Loader struct {
 	dataset *types.NameDataset
 	loaded  bool
+	named   map[string]*types.NameDataset // Loaded by LoadNamed, nil until then
 }
 
 // New creates a new Loader instance
@@ -55,35 +63,131 @@ func (l *Loader) LoadFromFile(filename string) error {
 	return nil
 }
 
-// LoadFromBytes loads name data from a byte array (supports gzip compression)
+// Reload loads name data from a protobuf file even if a dataset is already
+// loaded. The new data goes into a fresh dataset that replaces the current
+// one only when loading succeeds, so detectors still using the previous
+// dataset are unaffected; pass GetDataset to Detector.SetDataset to switch
+// them over. Reload must not be called concurrently with other Loader methods.
+func (l *Loader) Reload(filename string) error {
+	fresh := New()
+	if err := fresh.LoadFromFile(filename); err != nil {
+		return err
+	}
+
+	l.dataset = fresh.dataset
+	l.loaded = true
+	return nil
+}
+
+// Reset discards the loaded name data so the next Load call reads its
+// source again. The dataset previously returned by GetDataset is left intact
+// rather than cleared, so detectors still reading it are unaffected until they
+// are switched over with Detector.SetDataset. Like Reload, Reset must not be
+// called concurrently with other Loader methods.
+func (l *Loader) Reset() {
+	*l = *New()
+}
+
+// LoadNamed loads a protobuf file as a separate dataset stored under name,
+// such as one per region ("eu", "latam"), alongside the main dataset. Loading
+// a name again replaces its dataset only when the new file loads. Use
+// NamedDataset to retrieve it, typically for a detector.DetectorSet.
+func (l *Loader) LoadNamed(name, filename string) error {
+	fresh := New()
+	if err := fresh.LoadFromFile(filename); err != nil {
+		return err
+	}
+
+	if l.named == nil {
+		l.named = make(map[string]*types.NameDataset)
+	}
+	l.named[name] = fresh.dataset
+	return nil
+}
+
+// NamedDataset returns the dataset loaded by LoadNamed under name
+func (l *Loader) NamedDataset(name string) (*types.NameDataset, bool) {
+	dataset, exists := l.named[name]
+	return dataset, exists
+}
+
+// NamedDatasets returns the names passed to LoadNamed, sorted
+func (l *Loader) NamedDatasets() []string {
+	return slices.Sorted(maps.Keys(l.named))
+}
+
+// LoadFromBytes loads name data from a byte array (supports gzip and zstd compression)
 func (l *Loader) LoadFromBytes(data []byte) error {
+	return l.LoadFromReader(bytes.NewReader(data))
+}
+
+// LoadFromReader loads name data from r, such as an object streamed from
+// remote storage. Gzip and zstd compression are detected from their magic
+// bytes, so compressed data is decompressed as it is read rather than
+// buffered first.
+func (l *Loader) LoadFromReader(r io.Reader) error {
 	if l.loaded {
 		return nil // Already loaded
 	}
 
-	// Check if data is gzip compressed (magic bytes: 0x1f, 0x8b)
-	var decompressedData []byte
-	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
-		// Decompress gzip data
-		reader := bytes.NewReader(data)
-		gzipReader, err := gzip.NewReader(reader)
+	br := bufio.NewReader(r)
+	var source io.Reader = br
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		// Gzip magic bytes: 0x1f, 0x8b
+		gzipReader, err := gzip.NewReader(br)
 		if err != nil {
 			return fmt.Errorf("failed to create gzip reader: %w", err)
 		}
 		defer gzipReader.Close()
-
-		decompressedData, err = io.ReadAll(gzipReader)
+		source = gzipReader
+	} else if magic, err := br.Peek(4); err == nil && bytes.Equal(magic, zstdMagic) {
+		zstdReader, err := zstd.NewReader(br)
 		if err != nil {
-			return fmt.Errorf("failed to decompress data: %w", err)
+			return fmt.Errorf("failed to create zstd reader: %w", err)
 		}
-	} else {
-		// Data is not compressed
-		decompressedData = data
+		defer zstdReader.Close()
+		source = zstdReader
+	}
+
+	data, err := io.ReadAll(source)
+	if err != nil {
+		return fmt.Errorf("failed to read data: %w", err)
+	}
+
+	// Parse protobuf
+	var pbDataset names.CombinedNameDataset
+	if err := proto.Unmarshal(data, &pbDataset); err != nil {
+		return fmt.Errorf("failed to unmarshal protobuf: %w", err)
+	}
+
+	// Convert to internal format
+	l.convertToInternalFormat(&pbDataset)
+	l.loaded = true
+
+	return nil
+}
+
+// LoadFromFS loads name data from a protobuf file inside fsys, such as an
+// embed.FS. Files ending in .gz or .zst are decompressed transparently.
+func (l *Loader) LoadFromFS(fsys fs.FS, name string) error {
+	if l.loaded {
+		return nil // Already loaded
+	}
+
+	file, err := fsys.Open(name)
+	if err != nil {
+		return fmt.Errorf("failed to open file %s: %w", name, err)
+	}
+	defer file.Close()
+
+	data, err := readCompressed(name, file)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", name, err)
 	}
 
 	// Parse protobuf
 	var pbDataset names.CombinedNameDataset
-	if err := proto.Unmarshal(decompressedData, &pbDataset); err != nil {
+	if err := proto.Unmarshal(data, &pbDataset); err != nil {
 		return fmt.Errorf("failed to unmarshal protobuf: %w", err)
 	}
 
@@ -131,75 +235,309 @@ func (l *Loader) loadSingleDataset(filename string, isFirstNames bool) error {
 	if isFirstNames {
 		targetMap = l.dataset.FirstNames
 	}
-
-	for _, entry := range pbDataset.Entries {
-		nameData := &types.NameData{
-			Country: entry.Country,
-			Gender:  entry.Gender,
-			Rank:    entry.Rank,
-		}
-
-		// Store with normalized key for case-insensitive lookup
-		normalizedName := strings.ToUpper(strings.TrimSpace(entry.Name))
-		targetMap[normalizedName] = nameData
-	}
+	indexEntries(pbDataset.Entries, targetMap)
 
 	return nil
 }
 
-// readFile reads a file (with optional gzip decompression)
+// zstdMagic is the magic number that starts a zstd frame
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// readFile reads a file (with optional gzip or zstd decompression)
 func (l *Loader) readFile(filename string) ([]byte, error) {
-	if strings.HasSuffix(filename, ".gz") {
-		file, err := os.Open(filename)
-		if err != nil {
-			return nil, fmt.Errorf("failed to open file: %w", err)
-		}
-		defer file.Close()
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
 
-		gzipReader, err := gzip.NewReader(file)
+	return readCompressed(filename, file)
+}
+
+// readCompressed reads all of r, decompressing it according to the
+// extension of name
+func readCompressed(name string, r io.Reader) ([]byte, error) {
+	switch {
+	case strings.HasSuffix(name, ".gz"):
+		gzipReader, err := gzip.NewReader(r)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
 		}
 		defer gzipReader.Close()
 
-		// Use io.ReadAll instead of strings.Builder.ReadFrom
 		data, err := io.ReadAll(gzipReader)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read decompressed data: %w", err)
 		}
 
 		return data, nil
+	case strings.HasSuffix(name, ".zst"):
+		zstdReader, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd reader: %w", err)
+		}
+		defer zstdReader.Close()
+
+		data, err := io.ReadAll(zstdReader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read decompressed data: %w", err)
+		}
+
+		return data, nil
+	}
+
+	return io.ReadAll(r)
+}
+
+// WriteToFile writes the loaded dataset to filename as a CombinedNameDataset
+// protobuf, compressed according to its extension as LoadFromFile reads it:
+// gzip for ".gz", zstd for ".zst" and uncompressed otherwise. Entries are
+// written in name order with their aliases, so the file loads back into an
+// identical dataset and the same dataset always produces the same bytes. The
+// data goes to a temporary file renamed into place, so a concurrent Reload
+// never reads a partial file.
+func (l *Loader) WriteToFile(filename string) error {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(l.convertToProtobuf())
+	if err != nil {
+		return fmt.Errorf("failed to marshal protobuf: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if err := writeCompressed(filename, tmp, data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file %s: %w", filename, err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file %s: %w", filename, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filename, err)
+	}
+
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filename, err)
+	}
+	return nil
+}
+
+// writeCompressed writes data to w, compressing it according to the
+// extension of name
+func writeCompressed(name string, w io.Writer, data []byte) error {
+	var compressor io.WriteCloser
+	switch {
+	case strings.HasSuffix(name, ".gz"):
+		compressor = gzip.NewWriter(w)
+	case strings.HasSuffix(name, ".zst"):
+		zstdWriter, err := zstd.NewWriter(w)
+		if err != nil {
+			return fmt.Errorf("failed to create zstd writer: %w", err)
+		}
+		compressor = zstdWriter
+	default:
+		_, err := w.Write(data)
+		return err
 	}
 
-	return os.ReadFile(filename)
+	if _, err := compressor.Write(data); err != nil {
+		compressor.Close()
+		return err
+	}
+	return compressor.Close()
+}
+
+// convertToProtobuf converts the loaded dataset to protobuf format
+func (l *Loader) convertToProtobuf() *names.CombinedNameDataset {
+	return &names.CombinedNameDataset{
+		FirstNames: protobufEntries(l.dataset.FirstNames),
+		LastNames:  protobufEntries(l.dataset.LastNames),
+	}
+}
+
+// protobufEntries converts indexed entries to a protobuf dataset sorted by
+// name, skipping keys that only exist as aliases of another entry
+func protobufEntries(targetMap map[string]*types.NameData) *names.NameDataset {
+	entries := exportEntries(targetMap)
+
+	dataset := &names.NameDataset{Entries: make([]*names.NameEntry, 0, len(entries))}
+	for _, name := range slices.Sorted(maps.Keys(entries)) {
+		data := entries[name]
+		dataset.Entries = append(dataset.Entries, &names.NameEntry{
+			Name:    name,
+			Country: data.Country,
+			Gender:  data.Gender,
+			Rank:    data.Rank,
+			Aliases: data.Aliases,
+		})
+	}
+	return dataset
 }
 
 // convertToInternalFormat converts protobuf data to internal format
 func (l *Loader) convertToInternalFormat(pbDataset *names.CombinedNameDataset) {
-	// Convert first names
-	for _, entry := range pbDataset.FirstNames.Entries {
+	// Either role may be missing from a hand-built dataset
+	indexEntries(pbDataset.GetFirstNames().GetEntries(), l.dataset.FirstNames)
+	indexEntries(pbDataset.GetLastNames().GetEntries(), l.dataset.LastNames)
+	l.computeListSizes()
+}
+
+// computeListSizes records the per-country list sizes of the loaded names
+func (l *Loader) computeListSizes() {
+	l.dataset.FirstNameListSizes = types.ListSizes(l.dataset.FirstNames)
+	l.dataset.LastNameListSizes = types.ListSizes(l.dataset.LastNames)
+}
+
+// indexEntries converts protobuf entries and stores them in targetMap under
+// their normalized name. Aliases are indexed afterwards to the same NameData,
+// so an alias that collides with a distinct entry never overrides it; when
+// two entries claim the same alias, the first one wins.
+func indexEntries(entries []*names.NameEntry, targetMap map[string]*types.NameData) {
+	converted := make([]*types.NameData, len(entries))
+
+	for i, entry := range entries {
 		nameData := &types.NameData{
+			Name:    entry.Name,
 			Country: entry.Country,
 			Gender:  entry.Gender,
 			Rank:    entry.Rank,
+			MinRank: types.BestRank(entry.Rank),
+			Aliases: entry.Aliases,
 		}
-		
+		converted[i] = nameData
+
 		// Store with normalized key for case-insensitive lookup
-		normalizedName := strings.ToUpper(strings.TrimSpace(entry.Name))
-		l.dataset.FirstNames[normalizedName] = nameData
+		targetMap[normalizeKey(entry.Name)] = nameData
 	}
 
-	// Convert last names
-	for _, entry := range pbDataset.LastNames.Entries {
-		nameData := &types.NameData{
-			Country: entry.Country,
-			Gender:  entry.Gender,
-			Rank:    entry.Rank,
+	for i, entry := range entries {
+		for _, alias := range entry.Aliases {
+			key := normalizeKey(alias)
+			if _, exists := targetMap[key]; exists {
+				continue
+			}
+			targetMap[key] = converted[i]
+		}
+	}
+}
+
+// normalizeKey returns the case-insensitive map key for a name
+func normalizeKey(name string) string {
+	return strings.ToUpper(strings.TrimSpace(name))
+}
+
+// AddFirstName inserts a first name into the loaded dataset, replacing any
+// entry already stored under the same key. Names are keyed like loaded
+// entries: trimmed and uppercased, but not accent-stripped. Alias keys of a
+// replaced entry keep pointing at it, and the new entry's aliases are not
+// indexed.
+func (l *Loader) AddFirstName(name string, data *types.NameData) {
+	l.dataset.FirstNames[normalizeKey(name)] = withName(name, data)
+	growListSizes(l.dataset.FirstNameListSizes, data.Rank)
+}
+
+// AddLastName inserts a surname into the loaded dataset, replacing any entry
+// already stored under the same key
+func (l *Loader) AddLastName(name string, data *types.NameData) {
+	l.dataset.LastNames[normalizeKey(name)] = withName(name, data)
+	growListSizes(l.dataset.LastNameListSizes, data.Rank)
+}
+
+// growListSizes updates list sizes the loader computed for a name added
+// afterwards, leaving uncomputed (nil) sizes to be computed by their reader
+func growListSizes(sizes map[string]int32, rank map[string]int32) {
+	if sizes != nil {
+		types.GrowListSizes(sizes, rank)
+	}
+}
+
+// withName returns a copy of data with its Name set to name when empty
+func withName(name string, data *types.NameData) *types.NameData {
+	entry := *data
+	if entry.Name == "" {
+		entry.Name = strings.TrimSpace(name)
+	}
+	entry.MinRank = types.BestRank(entry.Rank)
+	return &entry
+}
+
+// Merge adds every first name and surname of other to the loaded dataset,
+// replacing existing entries with the same key as AddFirstName and
+// AddLastName do. Use MergeDataset to combine existing entries' country,
+// gender and rank data instead.
+func (l *Loader) Merge(other *types.NameDataset) {
+	for name, data := range other.FirstNames {
+		l.AddFirstName(name, data)
+	}
+	for name, data := range other.LastNames {
+		l.AddLastName(name, data)
+	}
+}
+
+// MergeDataset adds the first names and surnames of other to the loaded
+// dataset, such as domain-specific names layered on top of the embedded data.
+// Names are keyed like loaded entries (trimmed and uppercased, but not
+// accent-stripped), so "José" and "Jose" stay separate entries. When a name
+// exists in both, their country, gender and rank maps are merged key by key
+// with other's values winning, and aliases are combined into a new entry that
+// replaces the old one, so NameData already handed out is left unchanged.
+// Merge before creating detectors from the dataset, as they read its maps
+// without locking.
+func (l *Loader) MergeDataset(other *types.NameDataset) {
+	mergeNames(l.dataset.FirstNames, other.FirstNames, l.dataset.FirstNameListSizes)
+	mergeNames(l.dataset.LastNames, other.LastNames, l.dataset.LastNameListSizes)
+}
+
+// mergeNames merges the entries of source into targetMap, growing its list
+// sizes when they were computed. Merged entries are new copies swapped into
+// the map, along with alias keys that pointed at the old entry, so NameData
+// already handed out is never changed.
+func mergeNames(targetMap, source map[string]*types.NameData, sizes map[string]int32) {
+	for name, nameData := range source {
+		key := normalizeKey(name)
+		existing, exists := targetMap[key]
+		merged := &types.NameData{Name: strings.TrimSpace(name)}
+		if exists {
+			merged.Name = existing.Name
+			merged.Country = maps.Clone(existing.Country)
+			merged.Gender = maps.Clone(existing.Gender)
+			merged.Rank = maps.Clone(existing.Rank)
+			merged.Aliases = slices.Clone(existing.Aliases)
+		}
+
+		if merged.Country == nil {
+			merged.Country = make(map[string]float32, len(nameData.Country))
+		}
+		if merged.Gender == nil {
+			merged.Gender = make(map[string]float32, len(nameData.Gender))
+		}
+		if merged.Rank == nil {
+			merged.Rank = make(map[string]int32, len(nameData.Rank))
+		}
+		maps.Copy(merged.Country, nameData.Country)
+		maps.Copy(merged.Gender, nameData.Gender)
+		maps.Copy(merged.Rank, nameData.Rank)
+		merged.MinRank = types.BestRank(merged.Rank)
+		growListSizes(sizes, nameData.Rank)
+
+		for _, alias := range nameData.Aliases {
+			if !slices.Contains(merged.Aliases, alias) {
+				merged.Aliases = append(merged.Aliases, alias)
+			}
+		}
+
+		targetMap[key] = merged
+		if exists {
+			for _, alias := range merged.Aliases {
+				if aliasKey := normalizeKey(alias); targetMap[aliasKey] == existing {
+					targetMap[aliasKey] = merged
+				}
+			}
 		}
-		
-		// Store with normalized key for case-insensitive lookup
-		normalizedName := strings.ToUpper(strings.TrimSpace(entry.Name))
-		l.dataset.LastNames[normalizedName] = nameData
 	}
 }
 
@@ -222,6 +560,47 @@ func (l *Loader) GetStats() map[string]interface{} {
 	}
 }
 
+// Countries returns the distinct country codes present across all names, sorted
+func (l *Loader) Countries() []string {
+	seen := make(map[string]bool)
+	for _, nameMap := range []map[string]*types.NameData{l.dataset.FirstNames, l.dataset.LastNames} {
+		for _, nameData := range nameMap {
+			for country := range nameData.Country {
+				seen[country] = true
+			}
+			for country := range nameData.Rank {
+				seen[country] = true
+			}
+		}
+	}
+
+	return sortedKeys(seen)
+}
+
+// Genders returns the distinct gender categories present across all names, sorted
+func (l *Loader) Genders() []string {
+	seen := make(map[string]bool)
+	for _, nameMap := range []map[string]*types.NameData{l.dataset.FirstNames, l.dataset.LastNames} {
+		for _, nameData := range nameMap {
+			for gender := range nameData.Gender {
+				seen[gender] = true
+			}
+		}
+	}
+
+	return sortedKeys(seen)
+}
+
+// sortedKeys returns the keys of a set in ascending order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // LoadEmbedded loads the embedded dataset (convenience method)
 func (l *Loader) LoadEmbedded() error {
 	return l.LoadFromBytes(EmbeddedData)
@@ -0,0 +1,609 @@
+package loader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	names "github.com/montevive/go-name-detector/pkg/proto"
+	"github.com/montevive/go-name-detector/pkg/types"
+	"google.golang.org/protobuf/proto"
+)
+
+const testJSONDataset = `{
+  "first_names": {
+    "Catherine": {"country": {"GB": 0.6, "US": 0.4}, "gender": {"F": 1}, "rank": {"GB": 40, "US": 55}, "aliases": ["Kathryn", "Katherine"]},
+    "José": {"country": {"ES": 0.7, "MX": 0.3}, "gender": {"M": 0.98, "F": 0.02}, "rank": {"ES": 1, "MX": 2}}
+  },
+  "last_names": {
+    "García": {"country": {"ES": 0.5, "MX": 0.5}, "rank": {"ES": 1, "MX": 3}}
+  }
+}`
+
+func TestWriteToFile_RoundTrip(t *testing.T) {
+	l := New()
+	if err := l.LoadFromJSON(strings.NewReader(testJSONDataset)); err != nil {
+		t.Fatalf("LoadFromJSON failed: %v", err)
+	}
+	l.AddLastName("Zorvath", &types.NameData{
+		Country: map[string]float32{"US": 1},
+		Rank:    map[string]int32{"US": 500},
+	})
+
+	for _, name := range []string{"names.pb.gz", "names.pb.zst", "names.pb"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := l.WriteToFile(path); err != nil {
+				t.Fatalf("WriteToFile failed: %v", err)
+			}
+
+			reloaded := New()
+			if err := reloaded.LoadFromFile(path); err != nil {
+				t.Fatalf("LoadFromFile failed: %v", err)
+			}
+			if !reflect.DeepEqual(reloaded.GetDataset(), l.GetDataset()) {
+				t.Errorf("Expected the reloaded dataset to equal the written one")
+			}
+			if reloaded.GetDataset().FirstNames["KATHRYN"] != reloaded.GetDataset().FirstNames["CATHERINE"] {
+				t.Errorf("Expected aliases to share their entry after reloading")
+			}
+
+			// Writing the same dataset again produces the same bytes
+			first, _ := os.ReadFile(path)
+			if err := reloaded.WriteToFile(path); err != nil {
+				t.Fatalf("WriteToFile failed: %v", err)
+			}
+			second, _ := os.ReadFile(path)
+			if string(first) != string(second) {
+				t.Errorf("Expected identical output for an identical dataset")
+			}
+		})
+	}
+}
+
+func TestLoadNamed(t *testing.T) {
+	source := New()
+	if err := source.LoadFromJSON(strings.NewReader(testJSONDataset)); err != nil {
+		t.Fatalf("LoadFromJSON failed: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "eu.pb.gz")
+	if err := source.WriteToFile(path); err != nil {
+		t.Fatalf("WriteToFile failed: %v", err)
+	}
+
+	l := New()
+	if err := l.LoadNamed("eu", path); err != nil {
+		t.Fatalf("LoadNamed failed: %v", err)
+	}
+	if err := l.LoadNamed("latam", filepath.Join(t.TempDir(), "missing.pb.gz")); err == nil {
+		t.Errorf("Expected an error for a missing file")
+	}
+
+	eu, ok := l.NamedDataset("eu")
+	if !ok || !reflect.DeepEqual(eu, source.GetDataset()) {
+		t.Errorf("Expected the eu dataset to match its source")
+	}
+	if _, ok := l.NamedDataset("latam"); ok {
+		t.Errorf("Expected no dataset for a failed load")
+	}
+	if names := l.NamedDatasets(); !reflect.DeepEqual(names, []string{"eu"}) {
+		t.Errorf("Expected named datasets [eu], got %v", names)
+	}
+	if l.IsLoaded() || len(l.GetDataset().FirstNames) != 0 {
+		t.Errorf("Expected the main dataset to be left alone")
+	}
+}
+
+func TestListSizes(t *testing.T) {
+	l := New()
+	if err := l.LoadFromJSON(strings.NewReader(testJSONDataset)); err != nil {
+		t.Fatalf("LoadFromJSON failed: %v", err)
+	}
+
+	dataset := l.GetDataset()
+	if expected := map[string]int32{"GB": 40, "US": 55, "ES": 1, "MX": 2}; !reflect.DeepEqual(dataset.FirstNameListSizes, expected) {
+		t.Errorf("Expected first name list sizes %v, got %v", expected, dataset.FirstNameListSizes)
+	}
+
+	// Added and merged names extend the lists they rank past the end of
+	l.AddFirstName("Zorvath", &types.NameData{Rank: map[string]int32{"ES": 900}})
+	l.MergeDataset(&types.NameDataset{LastNames: map[string]*types.NameData{
+		"García": {Rank: map[string]int32{"AR": 12}},
+	}})
+	if expected := map[string]int32{"GB": 40, "US": 55, "ES": 900, "MX": 2}; !reflect.DeepEqual(dataset.FirstNameListSizes, expected) {
+		t.Errorf("Expected first name list sizes %v, got %v", expected, dataset.FirstNameListSizes)
+	}
+	if expected := map[string]int32{"ES": 1, "MX": 3, "AR": 12}; !reflect.DeepEqual(dataset.LastNameListSizes, expected) {
+		t.Errorf("Expected surname list sizes %v, got %v", expected, dataset.LastNameListSizes)
+	}
+
+	// So do names streamed in from CSV
+	if err := l.LoadFirstNamesCSV(strings.NewReader("name,country,rank\nZoraida,ES,1200\nZoraida,CL,7\n")); err != nil {
+		t.Fatalf("LoadFirstNamesCSV failed: %v", err)
+	}
+	if err := l.LoadLastNamesCSV(strings.NewReader("name,country,rank\nZorrilla,MX,45\n")); err != nil {
+		t.Fatalf("LoadLastNamesCSV failed: %v", err)
+	}
+	if expected := map[string]int32{"GB": 40, "US": 55, "ES": 1200, "MX": 2, "CL": 7}; !reflect.DeepEqual(dataset.FirstNameListSizes, expected) {
+		t.Errorf("Expected first name list sizes %v, got %v", expected, dataset.FirstNameListSizes)
+	}
+	if expected := map[string]int32{"ES": 1, "MX": 45, "AR": 12}; !reflect.DeepEqual(dataset.LastNameListSizes, expected) {
+		t.Errorf("Expected surname list sizes %v, got %v", expected, dataset.LastNameListSizes)
+	}
+}
+
+func TestLoadAuto(t *testing.T) {
+	source := New()
+	if err := source.LoadFromJSON(strings.NewReader(testJSONDataset)); err != nil {
+		t.Fatalf("LoadFromJSON failed: %v", err)
+	}
+	dir := t.TempDir()
+
+	// Protobuf under an extension that names no format is sniffed
+	pbPath := filepath.Join(dir, "names.pb.zst")
+	if err := source.WriteToFile(pbPath); err != nil {
+		t.Fatalf("WriteToFile failed: %v", err)
+	}
+	sniffedPath := filepath.Join(dir, "names.bundle")
+	if err := os.Rename(pbPath, sniffedPath); err != nil {
+		t.Fatal(err)
+	}
+
+	jsonPath := filepath.Join(dir, "names.json.gz")
+	writeGzip(t, jsonPath, testJSONDataset)
+
+	for _, path := range []string{sniffedPath, jsonPath} {
+		l := New()
+		if err := l.LoadAuto(path); err != nil {
+			t.Fatalf("LoadAuto(%s) failed: %v", filepath.Base(path), err)
+		}
+		if !reflect.DeepEqual(l.GetDataset(), source.GetDataset()) {
+			t.Errorf("LoadAuto(%s): expected the dataset to match its source", filepath.Base(path))
+		}
+	}
+
+	garbagePath := filepath.Join(dir, "names.dat")
+	if err := os.WriteFile(garbagePath, []byte("\x00\x01\x02 not a dataset"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := New().LoadAuto(garbagePath); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat for an unrecognized file, got %v", err)
+	}
+}
+
+func TestLoadAutoSeparate(t *testing.T) {
+	dir := t.TempDir()
+	firstPath := filepath.Join(dir, "first_names.csv.gz")
+	writeGzip(t, firstPath, "name,country,gender,rank\nZoraida,ES,F,120\nZoraida,MX,F,300\n")
+	lastPath := filepath.Join(dir, "last_names")
+	if err := os.WriteFile(lastPath, []byte("name,country,rank\nGarcía,ES,1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed load leaves the dataset untouched, so it can be retried
+	l := New()
+	if err := l.LoadAutoSeparate(firstPath, filepath.Join(dir, "missing.csv")); err == nil {
+		t.Fatal("Expected LoadAutoSeparate to fail on a missing surnames file")
+	}
+	if len(l.GetDataset().FirstNames) != 0 || l.IsLoaded() {
+		t.Errorf("Expected a failed load to leave the dataset empty, got %d first names", len(l.GetDataset().FirstNames))
+	}
+
+	if err := l.LoadAutoSeparate(firstPath, lastPath); err != nil {
+		t.Fatalf("LoadAutoSeparate failed: %v", err)
+	}
+	if zoraida := l.GetDataset().FirstNames["ZORAIDA"]; zoraida == nil || zoraida.Rank["MX"] != 300 {
+		t.Errorf("Expected Zoraida to load from gzipped CSV, got %+v", zoraida)
+	}
+	if _, exists := l.GetDataset().LastNames["GARCÍA"]; !exists {
+		t.Errorf("Expected García to load from sniffed CSV")
+	}
+
+	if err := New().LoadAuto(firstPath); err == nil || !strings.Contains(err.Error(), "LoadAutoSeparate") {
+		t.Errorf("Expected LoadAuto to point CSV files to LoadAutoSeparate, got %v", err)
+	}
+}
+
+func TestLoadAuto_SingleRoleProtobuf(t *testing.T) {
+	single, err := proto.Marshal(&names.NameDataset{Entries: []*names.NameEntry{
+		{Name: "Zoraida", Country: map[string]float32{"ES": 1}, Rank: map[string]int32{"ES": 120}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+
+	// Named like data/first_names.pb.gz, or sniffed without an extension
+	namedPath := filepath.Join(dir, "first_names.pb.gz")
+	writeGzip(t, namedPath, string(single))
+	sniffedPath := filepath.Join(dir, "first_names")
+	writeFile(t, sniffedPath, string(single))
+
+	for _, path := range []string{namedPath, sniffedPath} {
+		if err := New().LoadAuto(path); err == nil || !strings.Contains(err.Error(), "LoadAutoSeparate") {
+			t.Errorf("Expected LoadAuto(%s) to point to LoadAutoSeparate, got %v", filepath.Base(path), err)
+		}
+	}
+}
+
+func TestLoadAuto_CombinedWithOneRole(t *testing.T) {
+	data, err := proto.Marshal(&names.CombinedNameDataset{LastNames: &names.NameDataset{Entries: []*names.NameEntry{
+		{Name: "García", Country: map[string]float32{"ES": 1}, Rank: map[string]int32{"ES": 1}},
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "surnames_only.pb")
+	writeFile(t, path, string(data))
+
+	l := New()
+	if err := l.LoadAuto(path); err != nil {
+		t.Fatalf("LoadAuto failed: %v", err)
+	}
+	dataset := l.GetDataset()
+	if len(dataset.FirstNames) != 0 {
+		t.Errorf("Expected no first names, got %d", len(dataset.FirstNames))
+	}
+	if _, exists := dataset.LastNames["GARCÍA"]; !exists {
+		t.Errorf("Expected García to load from a surnames-only dataset")
+	}
+}
+
+// writeGzip writes content to path gzip-compressed
+func writeGzip(t *testing.T, path, content string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadCSV_MalformedRank(t *testing.T) {
+	const data = "name,country,gender,rank\nZoraida,ES,F,120\nZoraida,MX,F,n/a\nLucía,ES,F,5\n"
+
+	// File loaders fail with the file and line of the bad rank
+	dir := t.TempDir()
+	firstPath := filepath.Join(dir, "first.csv")
+	lastPath := filepath.Join(dir, "last.csv")
+	writeFile(t, firstPath, data)
+	writeFile(t, lastPath, "name,country,rank\nGarcía,ES,1\n")
+
+	err := New().LoadFromCSV(firstPath, lastPath)
+	if err == nil || !strings.Contains(err.Error(), firstPath+`:3: invalid rank "n/a" for Zoraida`) {
+		t.Errorf("Expected a line-numbered rank error, got %v", err)
+	}
+
+	// Reader loaders skip the row and keep the rest
+	l := New()
+	if err := l.LoadFirstNamesCSV(strings.NewReader(data)); err != nil {
+		t.Fatalf("LoadFirstNamesCSV failed: %v", err)
+	}
+	zoraida := l.GetDataset().FirstNames["ZORAIDA"]
+	if zoraida == nil || !reflect.DeepEqual(zoraida.Rank, map[string]int32{"ES": 120}) {
+		t.Errorf("Expected Zoraida ranked in ES only, got %+v", zoraida)
+	}
+	if l.GetDataset().FirstNames["LUCÍA"] == nil {
+		t.Errorf("Expected the rows after the malformed one to load")
+	}
+}
+
+func TestLoadFromCSV(t *testing.T) {
+	dir := t.TempDir()
+	firstPath := filepath.Join(dir, "first.csv")
+	lastPath := filepath.Join(dir, "last.csv.gz")
+	writeFile(t, firstPath, "\xEF\xBB\xBFname,country,gender,rank\n"+
+		"Zoraida,ES,F,120\n"+
+		"zoraida,mx,F,300\n"+
+		"\"Smith, Jr.\",US,M,40\n")
+	writeGzip(t, lastPath, "name,country,rank\nGarcía,ES,1\nGarcía,MX,3\n")
+
+	l := New()
+	if err := l.LoadFromCSV(firstPath, lastPath); err != nil {
+		t.Fatalf("LoadFromCSV failed: %v", err)
+	}
+	dataset := l.GetDataset()
+
+	// Rows for the same name merge into one entry, the BOM doesn't leak into
+	// the first column's header, and countries share the probability evenly
+	zoraida := dataset.FirstNames["ZORAIDA"]
+	if zoraida == nil {
+		t.Fatalf("Expected Zoraida to be loaded")
+	}
+	if !reflect.DeepEqual(zoraida.Rank, map[string]int32{"ES": 120, "MX": 300}) || zoraida.MinRank != 120 {
+		t.Errorf("Expected Zoraida ranked 120 in ES and 300 in MX, got %v (min %d)", zoraida.Rank, zoraida.MinRank)
+	}
+	if !reflect.DeepEqual(zoraida.Country, map[string]float32{"ES": 0.5, "MX": 0.5}) {
+		t.Errorf("Expected Zoraida split evenly between ES and MX, got %v", zoraida.Country)
+	}
+	if !reflect.DeepEqual(zoraida.Gender, map[string]float32{"F": 1}) {
+		t.Errorf("Expected Zoraida to be female, got %v", zoraida.Gender)
+	}
+
+	// Quoted fields keep their commas
+	if smith := dataset.FirstNames["SMITH, JR."]; smith == nil || smith.Name != "Smith, Jr." {
+		t.Errorf("Expected the quoted name \"Smith, Jr.\", got %+v", smith)
+	}
+	if garcia := dataset.LastNames["GARCÍA"]; garcia == nil || garcia.MinRank != 1 {
+		t.Errorf("Expected García from the gzipped surname file, got %+v", garcia)
+	}
+
+	// Custom header names
+	customPath := filepath.Join(dir, "custom.csv")
+	customLastPath := filepath.Join(dir, "custom_last.csv")
+	writeFile(t, customPath, "Given_Name,Nation,Sex,Position\nAitana,ES,F,7\n")
+	writeFile(t, customLastPath, "Given_Name,Nation,Position\nGarcía,ES,1\n")
+	columns := CSVColumns{Name: "given_name", Country: "nation", Gender: "sex", Rank: "position"}
+	custom := New()
+	if err := custom.LoadFromCSVWithColumns(customPath, customLastPath, columns); err != nil {
+		t.Fatalf("LoadFromCSVWithColumns failed: %v", err)
+	}
+	aitana := custom.GetDataset().FirstNames["AITANA"]
+	if aitana == nil || aitana.Rank["ES"] != 7 || aitana.Gender["F"] != 1 {
+		t.Errorf("Expected Aitana mapped from the custom columns, got %+v", aitana)
+	}
+	if err := New().LoadFromCSV(customPath, lastPath); err == nil || !strings.Contains(err.Error(), `header has no "name" column`) {
+		t.Errorf("Expected a missing column error with the default mapping, got %v", err)
+	}
+
+	// A bad rank names the file and line, counting the header and quoted fields
+	badPath := filepath.Join(dir, "bad.csv")
+	writeFile(t, badPath, "name,country,rank\n\"Lucía\",ES,5\nAna,ES,five\n")
+	err := New().LoadFromCSV(firstPath, badPath)
+	if err == nil || !strings.Contains(err.Error(), badPath+`:3: invalid rank "five" for Ana`) {
+		t.Errorf("Expected a line-numbered rank error, got %v", err)
+	}
+}
+
+func TestExportJSON_RoundTrip(t *testing.T) {
+	l := New()
+	if err := l.LoadFromJSON(strings.NewReader(testJSONDataset)); err != nil {
+		t.Fatalf("LoadFromJSON failed: %v", err)
+	}
+
+	var exported bytes.Buffer
+	if err := l.ExportJSON(&exported); err != nil {
+		t.Fatalf("ExportJSON failed: %v", err)
+	}
+
+	reloaded := New()
+	if err := reloaded.LoadFromJSON(bytes.NewReader(exported.Bytes())); err != nil {
+		t.Fatalf("LoadFromJSON of the export failed: %v", err)
+	}
+	if !reflect.DeepEqual(reloaded.GetDataset(), l.GetDataset()) {
+		t.Errorf("Expected the reimported dataset to equal the exported one")
+	}
+
+	catherine := reloaded.GetDataset().FirstNames["CATHERINE"]
+	if catherine == nil {
+		t.Fatalf("Expected Catherine after the round trip")
+	}
+	if !reflect.DeepEqual(catherine.Rank, map[string]int32{"GB": 40, "US": 55}) ||
+		!reflect.DeepEqual(catherine.Gender, map[string]float32{"F": 1}) ||
+		!reflect.DeepEqual(catherine.Aliases, []string{"Kathryn", "Katherine"}) {
+		t.Errorf("Expected Catherine's rank, gender and aliases to survive, got %+v", catherine)
+	}
+	if reloaded.GetDataset().FirstNames["KATHRYN"] != catherine {
+		t.Errorf("Expected aliases to share their entry after the round trip")
+	}
+
+	// Aliases are listed under their entry, not exported as names
+	if strings.Contains(exported.String(), `"Kathryn": {`) {
+		t.Errorf("Expected aliases not to be exported as separate entries:\n%s", exported.String())
+	}
+}
+
+func TestMergeDataset(t *testing.T) {
+	l := New()
+	if err := l.LoadFromJSON(strings.NewReader(testJSONDataset)); err != nil {
+		t.Fatalf("LoadFromJSON failed: %v", err)
+	}
+	dataset := l.GetDataset()
+	jose := dataset.FirstNames["JOSÉ"]
+
+	l.MergeDataset(&types.NameDataset{
+		FirstNames: map[string]*types.NameData{
+			" josé ": {
+				Country: map[string]float32{"MX": 0.6, "AR": 0.4},
+				Rank:    map[string]int32{"AR": 1},
+				Aliases: []string{"Pepe"},
+			},
+			"Zorvath":   {Rank: map[string]int32{"ES": 900}},
+			"Catherine": {Rank: map[string]int32{"CA": 30}},
+		},
+	})
+
+	// Existing entries are merged key by key into a copy, the merged values
+	// winning, so entries handed out before the merge never change
+	if expected := map[string]int32{"ES": 1, "MX": 2}; !reflect.DeepEqual(jose.Rank, expected) || jose.Aliases != nil {
+		t.Errorf("Expected the original entry to be left unchanged, got %v and %v", jose.Rank, jose.Aliases)
+	}
+	jose = dataset.FirstNames["JOSÉ"]
+	if expected := map[string]float32{"ES": 0.7, "MX": 0.6, "AR": 0.4}; !reflect.DeepEqual(jose.Country, expected) {
+		t.Errorf("Expected countries %v, got %v", expected, jose.Country)
+	}
+	if expected := map[string]int32{"ES": 1, "MX": 2, "AR": 1}; !reflect.DeepEqual(jose.Rank, expected) {
+		t.Errorf("Expected ranks %v, got %v", expected, jose.Rank)
+	}
+	if expected := map[string]float32{"M": 0.98, "F": 0.02}; !reflect.DeepEqual(jose.Gender, expected) {
+		t.Errorf("Expected the gender data to be kept, got %v", jose.Gender)
+	}
+	if !reflect.DeepEqual(jose.Aliases, []string{"Pepe"}) || jose.MinRank != 1 {
+		t.Errorf("Expected the alias Pepe and MinRank 1, got %v and %d", jose.Aliases, jose.MinRank)
+	}
+
+	// Alias keys follow the merged entry
+	if catherine := dataset.FirstNames["CATHERINE"]; dataset.FirstNames["KATHRYN"] != catherine || catherine.Rank["CA"] != 30 {
+		t.Errorf("Expected the alias Kathryn to point at the merged Catherine")
+	}
+
+	// New names are keyed like loaded ones, without stripping accents
+	if zorvath := dataset.FirstNames["ZORVATH"]; zorvath == nil || zorvath.Name != "Zorvath" || zorvath.MinRank != 900 {
+		t.Errorf("Expected Zorvath to be added under ZORVATH, got %+v", zorvath)
+	}
+	if _, exists := dataset.FirstNames["JOSE"]; exists {
+		t.Errorf("Expected no accent-stripped key to be added")
+	}
+}
+
+func TestAddAndMerge(t *testing.T) {
+	l := New()
+	if err := l.LoadFromJSON(strings.NewReader(testJSONDataset)); err != nil {
+		t.Fatalf("LoadFromJSON failed: %v", err)
+	}
+	dataset := l.GetDataset()
+
+	// New entries are stored under the trimmed, uppercased name, which fills
+	// in an empty Name and MinRank without modifying the caller's data
+	data := &types.NameData{Rank: map[string]int32{"CL": 30, "AR": 12}}
+	l.AddFirstName("  Aitana ", data)
+	aitana := dataset.FirstNames["AITANA"]
+	if aitana == nil || aitana.Name != "Aitana" || aitana.MinRank != 12 {
+		t.Errorf("Expected Aitana under AITANA with MinRank 12, got %+v", aitana)
+	}
+	if data.Name != "" || data.MinRank != 0 {
+		t.Errorf("Expected the added data to be copied, got %+v", data)
+	}
+
+	// Adding an existing name replaces the whole entry rather than merging it
+	l.AddLastName("garcía", &types.NameData{Name: "GARCÍA", Rank: map[string]int32{"PE": 7}})
+	garcia := dataset.LastNames["GARCÍA"]
+	if garcia.Name != "GARCÍA" || !reflect.DeepEqual(garcia.Rank, map[string]int32{"PE": 7}) || garcia.Country != nil {
+		t.Errorf("Expected García to be overwritten, got %+v", garcia)
+	}
+
+	// Merge overwrites the same way, in both roles
+	l.Merge(&types.NameDataset{
+		FirstNames: map[string]*types.NameData{
+			"Catherine": {Gender: map[string]float32{"F": 1}, Rank: map[string]int32{"IE": 3}},
+		},
+		LastNames: map[string]*types.NameData{
+			"Okafor": {Rank: map[string]int32{"NG": 20}},
+		},
+	})
+	catherine := dataset.FirstNames["CATHERINE"]
+	if !reflect.DeepEqual(catherine.Rank, map[string]int32{"IE": 3}) || catherine.Aliases != nil || catherine.MinRank != 3 {
+		t.Errorf("Expected Catherine to be overwritten, got %+v", catherine)
+	}
+	if okafor := dataset.LastNames["OKAFOR"]; okafor == nil || okafor.Name != "Okafor" {
+		t.Errorf("Expected Okafor to be added, got %+v", okafor)
+	}
+
+	// Alias keys still point at the replaced entry
+	if kathryn := dataset.FirstNames["KATHRYN"]; kathryn == nil || kathryn == catherine {
+		t.Errorf("Expected the alias Kathryn to keep the previous entry")
+	}
+}
+
+func TestLoadFromReader(t *testing.T) {
+	source := New()
+	if err := source.LoadFromJSON(strings.NewReader(testJSONDataset)); err != nil {
+		t.Fatalf("LoadFromJSON failed: %v", err)
+	}
+
+	for _, name := range []string{"names.pb", "names.pb.gz", "names.pb.zst"} {
+		t.Run(name, func(t *testing.T) {
+			l := New()
+			if err := l.LoadFromReader(bytes.NewReader(encodedDataset(t, source, name))); err != nil {
+				t.Fatalf("LoadFromReader failed: %v", err)
+			}
+			if !reflect.DeepEqual(l.GetDataset(), source.GetDataset()) {
+				t.Errorf("Expected the loaded dataset to equal its source")
+			}
+		})
+	}
+
+	if err := New().LoadFromReader(strings.NewReader("\x1f\x8b not gzip")); err == nil {
+		t.Errorf("Expected an error for corrupt gzip data")
+	}
+}
+
+// encodedDataset returns the bytes WriteToFile writes for l to a file with
+// the given name, compressed according to its extension
+func encodedDataset(t *testing.T, l *Loader, name string) []byte {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := l.WriteToFile(path); err != nil {
+		t.Fatalf("WriteToFile failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestLoadFromFS(t *testing.T) {
+	source := New()
+	if err := source.LoadFromJSON(strings.NewReader(testJSONDataset)); err != nil {
+		t.Fatalf("LoadFromJSON failed: %v", err)
+	}
+
+	fsys := fstest.MapFS{}
+	names := []string{"data/names.pb", "data/names.pb.gz", "data/names.pb.zst"}
+	for _, name := range names {
+		fsys[name] = &fstest.MapFile{Data: encodedDataset(t, source, filepath.Base(name))}
+	}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			l := New()
+			if err := l.LoadFromFS(fsys, name); err != nil {
+				t.Fatalf("LoadFromFS failed: %v", err)
+			}
+			if !reflect.DeepEqual(l.GetDataset(), source.GetDataset()) {
+				t.Errorf("Expected the loaded dataset to equal its source")
+			}
+		})
+	}
+
+	if err := New().LoadFromFS(fsys, "data/missing.pb.gz"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected fs.ErrNotExist for a missing file, got %v", err)
+	}
+}
+
+func TestCountriesAndGenders(t *testing.T) {
+	l := New()
+	if len(l.Countries()) != 0 || len(l.Genders()) != 0 {
+		t.Errorf("Expected no countries or genders before loading")
+	}
+
+	if err := l.LoadFromJSON(strings.NewReader(testJSONDataset)); err != nil {
+		t.Fatalf("LoadFromJSON failed: %v", err)
+	}
+	// A country known only from a rank, and a non-binary gender category
+	l.AddFirstName("Alex", &types.NameData{
+		Gender: map[string]float32{"X": 0.2, "M": 0.8},
+		Rank:   map[string]int32{"AR": 90},
+	})
+
+	if countries := l.Countries(); !reflect.DeepEqual(countries, []string{"AR", "ES", "GB", "MX", "US"}) {
+		t.Errorf("Expected sorted distinct countries, got %v", countries)
+	}
+	if genders := l.Genders(); !reflect.DeepEqual(genders, []string{"F", "M", "X"}) {
+		t.Errorf("Expected sorted distinct genders, got %v", genders)
+	}
+}
@@ -2,28 +2,166 @@ package types
 
 // NameData represents the metadata for a single name
 type NameData struct {
+	Name    string             // Spelling in the source data ("José"); may be empty
 	Country map[string]float32 // Country code → probability
 	Gender  map[string]float32 // "M"/"F" → probability (first names only)
 	Rank    map[string]int32   // Country code → rank (1 = most popular)
+
+	// MinRank caches the best (lowest) positive rank in Rank, as computed by
+	// BestRank, so scoring doesn't scan Rank on every lookup. The loader fills
+	// it in; zero means it wasn't computed and readers scan Rank instead.
+	// Update it whenever Rank changes.
+	MinRank int32
+
+	// Aliases are alternate spellings ("Katherine", "Kathryn" for "Catherine")
+	// indexed to this same entry, so they share its rank, country and gender
+	// data. An alias never replaces a distinct entry with the same spelling.
+	Aliases []string
+}
+
+// BestRank returns the best (lowest) positive rank in rank, or 0 when there
+// is none
+func BestRank(rank map[string]int32) int32 {
+	var best int32
+	for _, r := range rank {
+		if r > 0 && (best == 0 || r < best) {
+			best = r
+		}
+	}
+	return best
+}
+
+// ListSizes returns the length of each country's list in names, taken as
+// the largest rank any name holds there (country code → rank)
+func ListSizes(names map[string]*NameData) map[string]int32 {
+	sizes := make(map[string]int32)
+	for _, nameData := range names {
+		GrowListSizes(sizes, nameData.Rank)
+	}
+	return sizes
+}
+
+// GrowListSizes raises the sizes of the countries in rank that rank exceeds,
+// keeping sizes computed by ListSizes current as names are added
+func GrowListSizes(sizes map[string]int32, rank map[string]int32) {
+	for country, r := range rank {
+		if r > sizes[country] {
+			sizes[country] = r
+		}
+	}
 }
 
 // NameDataset holds the complete name databases
 type NameDataset struct {
 	FirstNames map[string]*NameData
 	LastNames  map[string]*NameData
+
+	// FirstNameListSizes and LastNameListSizes hold the length of each
+	// country's list for the role, as computed by ListSizes, so a rank can be
+	// read as a percentile: rank 50 of 500 is far rarer than rank 50 of
+	// 19,000. The loader fills them in; nil means they weren't computed and
+	// readers compute them instead.
+	FirstNameListSizes map[string]int32
+	LastNameListSizes  map[string]int32
 }
 
 // NameCombination represents a potential split of words into first names and surnames
 type NameCombination struct {
 	FirstNames []string
 	Surnames   []string
+	Reversed   bool // Surnames come before the first names in the input
+}
+
+// ScoredCombination is one candidate split of the input with its score, as
+// returned by Detector.DetectCandidates
+type ScoredCombination struct {
+	FirstNames []string `json:"first_names"`
+	Surnames   []string `json:"surnames"`
+	Reversed   bool     `json:"reversed,omitempty"` // Surnames come first in the input
+	Score      float64  `json:"score"`              // 0.0 to 1.0
+	Pattern    string   `json:"pattern"`
+	TopCountry string   `json:"top_country"`
+	Gender     string   `json:"gender"`
 }
 
 // PIIResult represents the result of PII name detection
 type PIIResult struct {
 	IsLikelyName bool    `json:"is_likely_name"`
 	Confidence   float64 `json:"confidence"` // 0.0 to 1.0
+	Analyzed     bool    `json:"analyzed"`   // False when the input could not be scored (wrong word count)
 	Details      NameDetails
+	Decision     Decision `json:"decision"` // Why the threshold decision went the way it did
+}
+
+// Decision explains a threshold decision. Reason is a one-sentence
+// justification built from the strongest factors, suitable for showing to
+// a reviewer as is; Supporting and Detracting hold the structured factors
+// for consumers that render their own explanation.
+type Decision struct {
+	Threshold  float64  `json:"threshold"`            // Threshold the confidence was compared against
+	Confidence float64  `json:"confidence"`           // Final confidence, capped at 1.0
+	Passed     bool     `json:"passed"`               // Confidence reached the threshold
+	Reason     string   `json:"reason"`               // e.g. "Passed: 2 of 2 tokens matched as names, ... (0.93 >= 0.70)"
+	Supporting []Factor `json:"supporting,omitempty"` // Factors that raised the score, strongest first
+	Detracting []Factor `json:"detracting,omitempty"` // Factors that lowered the score, strongest first
+}
+
+// Factor is a single signed contribution to a combination's score. The
+// impacts of all factors sum to the score before it is capped at 1.0.
+type Factor struct {
+	Name   string  `json:"name"`   // Stable identifier, e.g. "country_overlap"
+	Impact float64 `json:"impact"` // Change in score caused by this factor
+	Detail string  `json:"detail"` // Human-readable description
+}
+
+// PIIExplanation breaks down how a detection's confidence was computed.
+// BaseScore, the bonuses and the discount add up to the score before the
+// Adjustments multiply it in order, giving RawScore; FinalScore is RawScore
+// capped at 1.0, then calibrated if the detector's ScoreConfig enables it,
+// and equals Result.Confidence.
+type PIIExplanation struct {
+	Result     PIIResult        `json:"result"`
+	Components []ComponentScore `json:"components"` // One per token of the winning split
+
+	BaseScore              float64 `json:"base_score"`               // Average component score, including unknown tokens
+	GenderConsistencyBonus float64 `json:"gender_consistency_bonus"` // First names agree on gender
+	CountryOverlapBonus    float64 `json:"country_overlap_bonus"`    // First names and surnames share countries
+	RoleFitBonus           float64 `json:"role_fit_bonus"`           // Tokens rank best in their assigned role
+	MultipleNamesBonus     float64 `json:"multiple_names_bonus"`     // Three or more name parts
+	MissingCountryDiscount float64 `json:"missing_country_discount"` // Negative; matches without country data
+
+	Adjustments []ScoreAdjustment `json:"adjustments,omitempty"` // Pattern multipliers, in the order applied
+	RawScore    float64           `json:"raw_score"`             // Score before capping
+	FinalScore  float64           `json:"final_score"`           // Confidence, 0.0 to 1.0
+}
+
+// ComponentScore is the score of a single token in its assigned role
+type ComponentScore struct {
+	Token           string  `json:"token"`
+	Role            string  `json:"role"`             // "first_name" or "surname"
+	Matched         bool    `json:"matched"`          // Found in the dataset for its role
+	BaseScore       float64 `json:"base_score"`       // Score for being found at all
+	PopularityScore float64 `json:"popularity_score"` // Weighted bonus for the token's rank
+	Score           float64 `json:"score"`            // BaseScore + PopularityScore
+}
+
+// ScoreAdjustment is a multiplier applied to the score because of a pattern
+// in the winning split, such as a preposition used as a first name
+type ScoreAdjustment struct {
+	Name       string  `json:"name"`       // Factor name, e.g. "top_pair"
+	Multiplier float64 `json:"multiplier"` // e.g. 0.3 for a preposition used as a first name
+	Reason     string  `json:"reason"`
+}
+
+// ScoreTerm is one step of a result's score breakdown. Delta is what the
+// step added to the running score (negative for discounts); multiplicative
+// steps also report the Multiplier they applied, so the deltas always sum
+// to the raw score before clamping and calibration.
+type ScoreTerm struct {
+	Name       string  `json:"name"`                 // e.g. "base_match", "popularity", "top_pair"
+	Delta      float64 `json:"delta"`                // Change to the running score
+	Multiplier float64 `json:"multiplier,omitempty"` // Set for multiplicative steps only
+	Detail     string  `json:"detail"`
 }
 
 // NameDetails provides detailed information about the detected name
@@ -31,6 +169,144 @@ type NameDetails struct {
 	FirstNames []string `json:"first_names"` // Can be multiple: ["Jose", "Manuel"]
 	Surnames   []string `json:"surnames"`    // Can be multiple: ["Robles", "Hermoso"]
 	Pattern    string   `json:"pattern"`     // e.g., "2_first_2_last"
+	Order      string   `json:"order"`       // "western" (first names first), "eastern" (surnames first) or empty for a mononym
 	TopCountry string   `json:"top_country"` // Most likely country of origin
-	Gender     string   `json:"gender"`      // Predicted gender if applicable
-}
\ No newline at end of file
+	Gender     string   `json:"gender"`      // "Male", "Female", "Unisex" or "Unknown"
+	RoleFit    float64  `json:"role_fit"`    // Fraction of matched tokens that rank best in their assigned role
+
+	GenderConfidence float64 `json:"gender_confidence"` // Share of the leading gender in the first names' gender data
+	TopCountryName   string  `json:"top_country_name"`  // English name of TopCountry ("Spain"), empty when unknown
+
+	// CountryDistribution holds the matched names' country probabilities,
+	// normalized to sum to 1.0 ({"MX": 0.4, "ES": 0.3, ...}). Sort by value
+	// for display; TopCountry is its largest entry.
+	CountryDistribution map[string]float64 `json:"country_distribution,omitempty"`
+
+	// TopCountries holds the five largest CountryDistribution entries, most
+	// likely first, with ties ordered by country code
+	TopCountries []CountryScore `json:"top_countries,omitempty"`
+
+	// The FirstNames and Surnames found in the dataset for their role, so
+	// unmatched words that didn't contribute to the score can be told apart
+	MatchedFirstNames []string `json:"matched_first_names"`
+	MatchedSurnames   []string `json:"matched_surnames"`
+
+	Matches []NameMatchDetail `json:"matches,omitempty"` // How each matched component was found, in name order
+
+	// Average match score of the first names and of the surnames on their own,
+	// before the two sides are combined, with unknown tokens counting as zero
+	FirstNameConfidence float64 `json:"first_name_confidence"`
+	SurnameConfidence   float64 `json:"surname_confidence"`
+
+	// OriginUnknown is set when the matched names have no country data at all,
+	// so an empty TopCountry means a data gap rather than an ambiguous origin
+	OriginUnknown bool `json:"origin_unknown"`
+
+	// Positions in the caller's input words of the first names and surnames.
+	// Words dropped during cleaning are skipped, and a composed token such as
+	// "St. John" contributes the positions of every word it was built from.
+	FirstNameIndices []int `json:"first_name_indices,omitempty"`
+	SurnameIndices   []int `json:"surname_indices,omitempty"`
+
+	ComposedTokens []ComposedToken `json:"composed_tokens,omitempty"` // Words joined by a surname prefix
+
+	// CombinationsTruncated is set when the input produced more splits than the
+	// detector's combination cap allows and only the most balanced were scored
+	CombinationsTruncated bool `json:"combinations_truncated,omitempty"`
+
+	// AmbiguousTokens lists matched tokens that exist as both a first name and
+	// a surname, so their role in the name is uncertain (opt-in)
+	AmbiguousTokens []string `json:"ambiguous_tokens,omitempty"`
+
+	// ScoreBreakdown lists the additive and multiplicative steps that produced
+	// Confidence, in scoring order (opt-in via DetectorConfig.Explain)
+	ScoreBreakdown []ScoreTerm `json:"score_breakdown,omitempty"`
+
+	// Low confidence has two different causes: tokens that are real but rare
+	// names (ranked beyond 1000 everywhere) and tokens not in the dataset at all
+	RareTokens       []string `json:"rare_tokens,omitempty"`
+	UnknownTokens    []string `json:"unknown_tokens,omitempty"`
+	HasRareMatches   bool     `json:"has_rare_matches"`
+	HasUnknownTokens bool     `json:"has_unknown_tokens"`
+}
+
+// NameMatchDetail explains how a single token matched the dataset
+type NameMatchDetail struct {
+	Token      string  `json:"token"`      // Token as it appeared in the input
+	Role       string  `json:"role"`       // "first_name" or "surname"
+	Rank       int32   `json:"rank"`       // Best rank across countries, 0 when the entry has none
+	Popularity float64 `json:"popularity"` // Popularity contribution to the token's score
+	Lookup     string  `json:"lookup"`     // "exact", "normalized", "punctuation_stripped", "compound" or "fuzzy"
+}
+
+// ComposedToken records input words that were joined into a single name token
+// because the first word is a surname prefix ("St." + "John", "Mc" + "Donald")
+type ComposedToken struct {
+	Token string   `json:"token"` // Resulting token, e.g. "St. John"
+	Parts []string `json:"parts"` // Original words, e.g. ["St.", "John"]
+}
+
+// CoverageReport summarizes how much of a token sample the dataset knows about
+type CoverageReport struct {
+	Tokens     int     `json:"tokens"`      // Number of non-empty tokens examined
+	FirstNames float64 `json:"first_names"` // Fraction found as first names
+	Surnames   float64 `json:"surnames"`    // Fraction found as surnames
+	Either     float64 `json:"either"`      // Fraction found as a first name or surname
+	Neither    float64 `json:"neither"`     // Fraction not found in the dataset
+}
+
+// NameMatch represents a name found in free-form text
+type NameMatch struct {
+	Start  int       `json:"start"` // Byte offset of the match in the text
+	End    int       `json:"end"`   // Byte offset just past the match
+	Text   string    `json:"text"`  // Matched substring, text[Start:End]
+	Result PIIResult `json:"result"`
+}
+
+// DedupStats describes how much repetition a deduplicated batch contained
+type DedupStats struct {
+	Inputs     int     `json:"inputs"`      // Number of inputs in the batch
+	Unique     int     `json:"unique"`      // Number of distinct inputs scored
+	DedupRatio float64 `json:"dedup_ratio"` // Fraction of inputs that were duplicates
+}
+
+// BatchSummary aggregates the names detected in a batch of results
+type BatchSummary struct {
+	Processed     int            `json:"processed"`      // Number of analyzed results summarized
+	Skipped       int            `json:"skipped"`        // Number of results that could not be analyzed, such as lines of the wrong length
+	Detected      int            `json:"detected"`       // Number of results flagged as names
+	DetectionRate float64        `json:"detection_rate"` // Fraction of analyzed results flagged as names, 0 when none were processed
+	Genders       map[string]int `json:"genders"`        // Detected names per predicted gender, "Unknown" when none
+	TopCountries  []CountryCount `json:"top_countries"`  // Most common countries of detected names, most frequent first
+}
+
+// CountryScore is a country's share of a name's aggregated country
+// probabilities
+type CountryScore struct {
+	Country string  `json:"country"`
+	Score   float64 `json:"score"`
+}
+
+// CountryCount is the number of detected names attributed to a country
+type CountryCount struct {
+	Country string `json:"country"`
+	Count   int    `json:"count"`
+}
+
+// CacheStats describes the state of a detector's result cache
+type CacheStats struct {
+	Hits      int64   `json:"hits"`      // Lookups answered from the cache
+	Misses    int64   `json:"misses"`    // Lookups that had to be scored
+	Evictions int64   `json:"evictions"` // Entries dropped to stay within MaxSize
+	Size      int     `json:"size"`      // Entries currently cached
+	MaxSize   int     `json:"max_size"`  // Configured maximum number of entries
+	HitRate   float64 `json:"hit_rate"`  // Fraction of lookups that were hits
+}
+
+// FirstLastResult is the outcome of checking a name entered into separate
+// first name and last name fields
+type FirstLastResult struct {
+	PIIResult
+	SuggestSwap       bool    `json:"suggest_swap"`       // Fields score much better swapped
+	SwappedConfidence float64 `json:"swapped_confidence"` // Confidence with the fields swapped
+}
@@ -28,6 +28,7 @@ type NameEntry struct {
 	Country       map[string]float32     `protobuf:"bytes,2,rep,name=country,proto3" json:"country,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed32,2,opt,name=value"` // Country code → probability
 	Gender        map[string]float32     `protobuf:"bytes,3,rep,name=gender,proto3" json:"gender,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed32,2,opt,name=value"`   // "M"/"F" → probability (only for first names)
 	Rank          map[string]int32       `protobuf:"bytes,4,rep,name=rank,proto3" json:"rank,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`        // Country code → rank (1 = most popular)
+	Aliases       []string               `protobuf:"bytes,5,rep,name=aliases,proto3" json:"aliases,omitempty"`                                                                             // Alternate spellings that share this entry's data
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -90,6 +91,13 @@ func (x *NameEntry) GetRank() map[string]int32 {
 	return nil
 }
 
+func (x *NameEntry) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
 // Container for a complete name dataset (first names or last names)
 type NameDataset struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -192,12 +200,13 @@ var File_proto_names_proto protoreflect.FileDescriptor
 
 const file_proto_names_proto_rawDesc = "" +
 	"\n" +
-	"\x11proto/names.proto\x12\x05names\"\xee\x02\n" +
+	"\x11proto/names.proto\x12\x05names\"\x88\x03\n" +
 	"\tNameEntry\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x127\n" +
 	"\acountry\x18\x02 \x03(\v2\x1d.names.NameEntry.CountryEntryR\acountry\x124\n" +
 	"\x06gender\x18\x03 \x03(\v2\x1c.names.NameEntry.GenderEntryR\x06gender\x12.\n" +
-	"\x04rank\x18\x04 \x03(\v2\x1a.names.NameEntry.RankEntryR\x04rank\x1a:\n" +
+	"\x04rank\x18\x04 \x03(\v2\x1a.names.NameEntry.RankEntryR\x04rank\x12\x18\n" +
+	"\aaliases\x18\x05 \x03(\tR\aaliases\x1a:\n" +
 	"\fCountryEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\x02R\x05value:\x028\x01\x1a9\n" +